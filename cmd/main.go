@@ -9,12 +9,24 @@ import (
 )
 
 func main() {
-	if err := api.Init(); err != nil {
+	config, err := api.LoadConfig()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	if err := api.Init(config); err != nil {
 		log.Panic(err)
 	}
 
 	router := gin.Default()
+	router.Use(api.CorrelationID())
 	router.POST("/flight_paths", api.CalculateFlightPath)
+	router.POST("/flight_paths/bulk", api.CalculateFlightPaths)
+	router.GET("/routes", api.FindRoutes)
+	router.POST("/flight_paths:method", api.CustomMethods(map[string]gin.HandlerFunc{
+		"diff":  api.DiffFlightPaths,
+		"merge": api.MergeFlightPath,
+	}))
 
 	// listen and serve on 0.0.0.0:8080 (for windows "localhost:8080")
 	if err := router.Run(); err != nil {
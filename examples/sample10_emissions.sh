@@ -0,0 +1,6 @@
+#!/bin/bash
+
+# Requires the server to be started with AIRPORTS_FILE=data/airports.dat
+curl -0 -v 'http://localhost:8080/flight_paths?emissions=true&cabin_class=business' \
+-H 'Content-Type: application/json' \
+--data-raw '{"flight_legs": [["ATL", "GSO"], ["SFO", "ATL"]], "flight_leg_format": "object"}'
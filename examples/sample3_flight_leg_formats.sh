@@ -0,0 +1,16 @@
+#!/bin/bash
+
+curl -0 -v http://localhost:8080/flight_paths \
+-H "Expect:" \
+-H 'Content-Type: application/json' \
+--data-binary @- << EOF
+{
+    "flight_legs": [
+        "IND-EWR",
+        {"departure": "SFO", "arrival": "ATL", "carrier": "DL"},
+        ["GSO", "IND"],
+        {"departure": "ATL", "arrival": "GSO"}
+    ],
+    "flight_leg_format": "object"
+}
+EOF
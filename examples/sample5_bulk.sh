@@ -0,0 +1,10 @@
+#!/bin/bash
+
+curl -0 -v "http://localhost:8080/flight_paths/bulk?order=completion" \
+-H "Expect:" \
+-H 'Content-Type: application/x-ndjson' \
+--data-binary @- << EOF
+{"flight_legs": [["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]}
+{"flight_legs": [["SFO", "EWR"]]}
+{"flight_legs": [["ATL", "EWR"], ["EWR", "ATL"]]}
+EOF
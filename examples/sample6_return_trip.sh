@@ -0,0 +1,13 @@
+#!/bin/bash
+
+curl -0 -v 'http://localhost:8080/flight_paths?return_trip=true&stay=72h' \
+-H "Expect:" \
+-H 'Content-Type: text/csv' \
+-H 'Accept: text/csv' \
+--data-binary @- << EOF
+departure,arrival,departure_time,arrival_time
+IND,EWR,2024-03-25T15:10:00-04:00,2024-03-25T16:55:00-04:00
+SFO,ATL,2024-03-25T08:05:00-07:00,2024-03-25T15:40:00-04:00
+GSO,IND,2024-03-25T12:00:00-04:00,2024-03-25T13:20:00-04:00
+ATL,GSO,2024-03-25T16:30:00-04:00,2024-03-25T17:35:00-04:00
+EOF
@@ -0,0 +1,11 @@
+#!/bin/bash
+
+curl -0 -v http://localhost:8080/flight_paths:diff \
+-H "Expect:" \
+-H 'Content-Type: application/json; charset=utf-8' \
+--data-binary @- << EOF
+{
+    "before": {"flight_legs": [["IND", "EWR"], ["SFO", "ATL"], ["GSO", "IND"], ["ATL", "GSO"]]},
+    "after": {"flight_legs": [["ORD", "EWR"], ["SFO", "ATL"], ["ATL", "ORD"]]}
+}
+EOF
@@ -0,0 +1,14 @@
+#!/bin/bash
+
+curl -0 -v http://localhost:8080/flight_paths:merge \
+-H "Expect:" \
+-H 'Content-Type: application/json; charset=utf-8' \
+--data-binary @- << EOF
+{
+    "fragments": [
+        {"source": "airline", "flight_legs": [["SFO", "ATL"], ["ATL", "GSO"]]},
+        {"source": "agency", "flight_legs": [["ATL", "GSO"], ["GSO", "IND"]]},
+        {"source": "booking tool", "flight_legs": [["IND", "EWR"]]}
+    ]
+}
+EOF
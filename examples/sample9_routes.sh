@@ -0,0 +1,4 @@
+#!/bin/bash
+
+# Requires the server to be started with ROUTES_FILE=data/routes.dat and AIRPORTS_FILE=data/airports.dat
+curl -0 -v 'http://localhost:8080/routes?from=LAX&to=SEA&max_stops=2'
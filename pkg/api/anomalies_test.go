@@ -0,0 +1,74 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const anomaliesPayload = `{"flight_legs": [
+	{"departure": "ATL", "arrival": "GSO", "departure_time": "2024-03-25T15:50:00-04:00", "arrival_time": "2024-03-25T17:00:00-04:00"},
+	{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:00:00-07:00", "arrival_time": "2024-03-25T16:00:00-04:00"},
+	{"departure": "GSO", "arrival": "IND", "departure_time": "2024-03-25T17:20:00-04:00", "arrival_time": "2024-03-25T17:40:00-04:00"}
+], "flight_leg_format": "string"}`
+
+func TestCalculateFlightPath_Anomalies(t *testing.T) {
+	router := newTestRouter(t, routeNetworkConfig())
+
+	response := postDuplicates(router, "", anomaliesPayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "SFO",
+		"destination": "IND",
+		"flight_legs": ["SFO-ATL", "ATL-GSO", "GSO-IND"],
+		"warnings": [{
+			"code": "unknown_route",
+			"message": "No airline is known to fly nonstop between the airports of the flight leg.",
+			"flight_leg": "GSO-IND"
+		}],
+		"anomalies": [
+			{
+				"code": "overlapping_flight_legs",
+				"severity": "high",
+				"message": "A flight leg departs before the one before it arrives, so the traveler would be in two places at once.",
+				"flight_legs": ["SFO-ATL", "ATL-GSO"]
+			},
+			{
+				"code": "implausible_speed",
+				"severity": "high",
+				"message": "The flight leg would have been flown faster than an airliner can fly, or it arrives before it departs.",
+				"flight_legs": ["GSO-IND"]
+			},
+			{
+				"code": "short_turnaround",
+				"severity": "low",
+				"message": "The connection between the flight legs is too short to be made.",
+				"flight_legs": ["ATL-GSO", "GSO-IND"]
+			}
+		]
+	}`)
+}
+
+func TestCalculateFlightPath_AnomaliesWithoutAirports(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postDuplicates(router, "", anomaliesPayload, "es")
+
+	assert.Equal(t, response.Code, 200)
+	assert.NotContains(t, response.Body.String(), "implausible_speed")
+	assert.Contains(t, response.Body.String(), `"code":"overlapping_flight_legs","severity":"high",`+
+		`"message":"Un tramo de vuelo sale antes de que llegue el anterior, por lo que el viajero estaría en dos lugares a la vez."`)
+}
+
+func TestCalculateFlightPaths_Anomalies(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsBulk(router, `{"flight_legs": [`+
+		`{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:00:00-07:00", "arrival_time": "2024-03-25T16:00:00-04:00"}, `+
+		`{"departure": "ATL", "arrival": "GSO", "departure_time": "2024-03-25T16:05:00-04:00", "arrival_time": "2024-03-25T17:00:00-04:00"}`+
+		`], "flight_leg_format": "string"}`+"\n", "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(), `"anomalies":[{"code":"short_turnaround","severity":"medium",`)
+}
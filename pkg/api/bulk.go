@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const MIMENDJSON = "application/x-ndjson"
+
+// The order in which the results of a bulk request are written
+const (
+	BulkOrderInput      = "input"
+	BulkOrderCompletion = "completion"
+)
+
+// maxBulkLineSize is the size of the longest line a bulk request can have
+const maxBulkLineSize = 1024 * 1024
+
+var ErrInvalidBulkOrder = errors.New("invalid bulk order - must be either input or completion")
+
+// BulkResult is written as one line of a bulk response, with either the flight path or the error of one line of the
+// bulk request.
+type BulkResult struct {
+	// Line is the number of the request line, starting at 1
+	Line       int               `json:"line"`
+	FlightPath *model.FlightPath `json:"flight_path,omitempty"`
+	Error      *ErrorResponse    `json:"error,omitempty"`
+}
+
+type bulkLine struct {
+	number int
+	data   []byte
+	err    error
+}
+
+// CalculateFlightPaths calculates the flight paths of a newline-delimited JSON body, where each line is a request of
+// CalculateFlightPath. Lines are calculated as they arrive, and the results are streamed back as newline-delimited
+// JSON, either in the order of the request lines or in the order they were completed.
+func CalculateFlightPaths(c *gin.Context) {
+	order := c.DefaultQuery("order", BulkOrderInput)
+	if order != BulkOrderInput && order != BulkOrderCompletion {
+		abortWithError(c, NewValidationError(ErrInvalidBulkOrder))
+		return
+	}
+
+	options, err := parseCalculationOptions(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+
+	// Without full duplex, the HTTP/1 server stops reading the request body as soon as the first result is written.
+	if err := http.NewResponseController(c.Writer).EnableFullDuplex(); err != nil {
+		log.WithError(err).Warn("Unable to enable full duplex; the request body may be cut short")
+	}
+
+	correlationID := getCorrelationID(c)
+	language := negotiateLanguage(c.GetHeader("Accept-Language"))
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	results := streamBulk(ctx, c.Request.Body, order, func(ctx context.Context, line bulkLine) BulkResult {
+		return calculateBulkLine(ctx, line, options, correlationID, language)
+	})
+
+	c.Header("Content-Type", MIMENDJSON)
+	c.Header("Content-Language", language)
+	c.Header("Vary", "Accept-Language")
+	c.Status(200)
+
+	c.Stream(func(w io.Writer) bool {
+		result, ok := <-results
+		if !ok {
+			return false
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			log.WithField("CorrelationID", correlationID).WithError(err).Error("Unable to write bulk result")
+			return false
+		}
+		return true
+	})
+}
+
+func calculateBulkLine(
+	ctx context.Context, line bulkLine, options domain.CalculationOptions, correlationID, language string,
+) BulkResult {
+	result := BulkResult{Line: line.number}
+
+	flightPath, err := calculateBulkFlightPath(ctx, line, options)
+	if err != nil {
+		logError(log.WithFields(logrus.Fields{"CorrelationID": correlationID, "Line": line.number}), err)
+		result.Error = NewErrorResponse(err, correlationID, language)
+		return result
+	}
+
+	detectAnomalies(flightPath)
+	describeFindings(flightPath, language)
+	result.FlightPath = flightPath
+	return result
+}
+
+func calculateBulkFlightPath(
+	ctx context.Context, line bulkLine, options domain.CalculationOptions,
+) (*model.FlightPath, error) {
+	if errors.Is(line.err, bufio.ErrTooLong) {
+		return nil, NewValidationError(line.err)
+	}
+	if line.err != nil {
+		return nil, line.err
+	}
+
+	var request CalculateFlightPathRequest
+	if err := validateRequest(&request, json.Unmarshal(line.data, &request)); err != nil {
+		return nil, err
+	}
+	return calculateFlightPath(ctx, &request, options)
+}
+
+// streamBulk processes the lines of the body concurrently, up to config.BulkConcurrency lines at a time, and returns
+// their results in the given order. Lines are only read as fast as the results are consumed, so that the body is never
+// buffered as a whole.
+func streamBulk(ctx context.Context, body io.Reader, order string,
+	process func(context.Context, bulkLine) BulkResult) <-chan BulkResult {
+
+	lines := make(chan bulkLine)
+	go scanBulkLines(ctx, body, lines)
+
+	if order == BulkOrderCompletion {
+		return processInCompletionOrder(ctx, lines, config.BulkConcurrency, process)
+	}
+	return processInInputOrder(ctx, lines, config.BulkConcurrency, process)
+}
+
+// scanBulkLines skips blank lines. A line that is too long is sent along with bufio.ErrTooLong, and the scan goes on
+// with the next line. A line that cannot be read at all is sent along with its error, and ends the scan.
+func scanBulkLines(ctx context.Context, body io.Reader, lines chan<- bulkLine) {
+	defer close(lines)
+
+	reader := bufio.NewReader(body)
+	for number := 1; ; number++ {
+		data, tooLong, err := readBulkLine(reader)
+		if err != nil && !errors.Is(err, io.EOF) {
+			send(ctx, lines, bulkLine{number: number, err: err})
+			return
+		}
+
+		line := bulkLine{number: number, data: bytes.TrimSpace(data)}
+		if tooLong {
+			line = bulkLine{number: number, err: bufio.ErrTooLong}
+		}
+		if (line.err != nil || len(line.data) > 0) && !send(ctx, lines, line) {
+			return
+		}
+
+		if errors.Is(err, io.EOF) {
+			return
+		}
+	}
+}
+
+// readBulkLine reads the next line, up to maxBulkLineSize bytes. The rest of a line that is too long is discarded, so
+// that the next line can still be read. The error is io.EOF once the body has no lines left.
+func readBulkLine(reader *bufio.Reader) (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			// the reader reuses its buffer, so the chunk must be copied before the next read
+			line = append(line, chunk...)
+			tooLong = len(bytes.TrimSuffix(line, []byte("\n"))) > maxBulkLineSize
+			if tooLong {
+				line = nil
+			}
+		}
+
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, tooLong, err
+		}
+	}
+}
+
+func processInCompletionOrder(ctx context.Context, lines <-chan bulkLine, concurrency int,
+	process func(context.Context, bulkLine) BulkResult) <-chan BulkResult {
+
+	results := make(chan BulkResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for line := range lines {
+				if !send(ctx, results, process(ctx, line)) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// processInInputOrder queues the pending result of each line as soon as its processing starts, so that results are
+// written in the order of the queue. The queue is bounded, so that a slow line holds back the reading of the body
+// instead of piling up the results of the lines after it.
+func processInInputOrder(ctx context.Context, lines <-chan bulkLine, concurrency int,
+	process func(context.Context, bulkLine) BulkResult) <-chan BulkResult {
+
+	slots := make(chan struct{}, concurrency)
+	pending := make(chan chan BulkResult, concurrency)
+	results := make(chan BulkResult)
+
+	go func() {
+		defer close(pending)
+		for line := range lines {
+			if !send(ctx, slots, struct{}{}) {
+				return
+			}
+
+			result := make(chan BulkResult, 1)
+			go func(line bulkLine) {
+				result <- process(ctx, line)
+				<-slots
+			}(line)
+
+			if !send(ctx, pending, result) {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+		for result := range pending {
+			select {
+			case r := <-result:
+				if !send(ctx, results, r) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// send gives up once the context is done, which happens when the client goes away
+func send[T any](ctx context.Context, c chan<- T, value T) bool {
+	select {
+	case c <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func newBulkTestRouter(t *testing.T, c Config) *gin.Engine {
+	router := newTestRouter(t, c)
+	router.POST("/flight_paths/bulk", CalculateFlightPaths)
+	return router
+}
+
+// streamRecorder can be used by gin's Context.Stream, which requires an http.CloseNotifier
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r streamRecorder) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func postFlightPathsBulk(router *gin.Engine, payload, query string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths/bulk"+query, strings.NewReader(payload))
+	request.Header.Set("Content-Type", MIMENDJSON)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(streamRecorder{recorder}, request)
+	return recorder
+}
+
+func decodeBulkResults(t *testing.T, body string) []BulkResult {
+	var results []BulkResult
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		var result BulkResult
+		assert.NoError(t, json.Unmarshal([]byte(line), &result))
+		results = append(results, result)
+	}
+	return results
+}
+
+func collectBulkResults(results <-chan BulkResult) []int {
+	var lines []int
+	for result := range results {
+		lines = append(lines, result.Line)
+	}
+	return lines
+}
+
+// slowFirstLine makes the first line take longer than the others, so that it completes last
+func slowFirstLine(_ context.Context, line bulkLine) BulkResult {
+	if line.number == 1 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	return BulkResult{Line: line.number}
+}
+
+func TestCalculateFlightPaths(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsBulk(router, `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`+"\n"+
+		"\n"+
+		`{"flight_legs": [["ATL", "EWR"], ["EWR", "ATL"]]}`+"\n"+
+		`{"flight_legs": [["IND", "EWR"]], "flight_leg_format": "string"}`, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("Content-Type"), MIMENDJSON)
+
+	lines := strings.Split(strings.TrimSuffix(response.Body.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.JSONEq(t, lines[0],
+		`{"line":1,"flight_path":{"origin":"SFO","destination":"EWR","flight_legs":[["SFO","ATL"],["ATL","EWR"]]}}`)
+	assert.JSONEq(t, lines[2],
+		`{"line":4,"flight_path":{"origin":"IND","destination":"EWR","flight_legs":["IND-EWR"]}}`)
+
+	results := decodeBulkResults(t, response.Body.String())
+	assert.Equal(t, results[1].Line, 3)
+	assert.Nil(t, results[1].FlightPath)
+	assert.Equal(t, results[1].Error.Code, "flight_path_loop")
+	assert.Equal(t, results[1].Error.CorrelationID, response.Header().Get(CorrelationIDHeader))
+}
+
+func TestCalculateFlightPaths_ValidationErrors(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsBulk(router, `{"flight_legs": [["ATL", "E5R"]]}`+"\n"+
+		`{"flight_legs": [["ATL"`+"\n"+
+		`{"flight_legs": [["SFO", "ATL"]]}`+"\n", "")
+
+	assert.Equal(t, response.Code, 200)
+
+	results := decodeBulkResults(t, response.Body.String())
+	assert.Len(t, results, 3)
+	assert.Equal(t, results[0].Error.Code, "invalid_airport_code")
+	assert.Equal(t, results[0].Error.InvalidParams[0].Path, "flight_legs[0][1]")
+	assert.Equal(t, results[1].Error.Code, "malformed_request")
+	assert.Nil(t, results[2].Error)
+	assert.Equal(t, results[2].FlightPath.Origin, model.AirportCode("SFO"))
+}
+
+func TestCalculateFlightPaths_LineTooLong(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	longLine := `{"flight_legs": [` + strings.Repeat(`["SFO", "ATL"], `, maxBulkLineSize/16) + `]}`
+	response := postFlightPathsBulk(router, `{"flight_legs": [["SFO", "ATL"]]}`+"\n"+
+		longLine+"\n"+
+		`{"flight_legs": [["ATL", "GSO"]]}`+"\n"+
+		longLine, "")
+
+	results := decodeBulkResults(t, response.Body.String())
+	assert.Len(t, results, 4)
+	assert.Nil(t, results[0].Error)
+	assert.Equal(t, results[1].Line, 2)
+	assert.Equal(t, results[1].Error.Code, "malformed_request")
+	assert.Equal(t, results[2].Line, 3)
+	assert.Nil(t, results[2].Error)
+	assert.Equal(t, results[2].FlightPath.Origin, model.AirportCode("ATL"))
+	assert.Equal(t, results[3].Line, 4)
+	assert.Equal(t, results[3].Error.Code, "malformed_request")
+}
+
+func TestCalculateFlightPaths_InvalidOrder(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsBulk(router, `{"flight_legs": [["SFO", "ATL"]]}`, "?order=random")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_bulk_order"`)
+}
+
+func TestCalculateFlightPaths_Streaming(t *testing.T) {
+	server := httptest.NewServer(newBulkTestRouter(t, DefaultConfig()))
+	defer server.Close()
+
+	body, requestWriter := io.Pipe()
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/flight_paths/bulk", body)
+	assert.NoError(t, err)
+
+	responses := make(chan *http.Response)
+	go func() {
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		responses <- response
+	}()
+
+	// each result must be received before the next line is sent, so the body cannot have been buffered
+	_, err = fmt.Fprintln(requestWriter, `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+	assert.NoError(t, err)
+
+	response := <-responses
+	defer response.Body.Close()
+	reader := bufio.NewReader(response.Body)
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Contains(t, line, `"line":1`)
+
+	_, err = fmt.Fprintln(requestWriter, `{"flight_legs": [["IND", "EWR"]]}`)
+	assert.NoError(t, err)
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Contains(t, line, `"line":2`)
+
+	assert.NoError(t, requestWriter.Close())
+	_, err = reader.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamBulk_InputOrder(t *testing.T) {
+	config.BulkConcurrency = 4
+	t.Cleanup(func() {
+		config = DefaultConfig()
+	})
+
+	results := streamBulk(context.Background(), strings.NewReader("a\nb\nc\nd\n"), BulkOrderInput, slowFirstLine)
+
+	assert.Equal(t, collectBulkResults(results), []int{1, 2, 3, 4})
+}
+
+func TestStreamBulk_CompletionOrder(t *testing.T) {
+	config.BulkConcurrency = 4
+	t.Cleanup(func() {
+		config = DefaultConfig()
+	})
+
+	results := streamBulk(context.Background(), strings.NewReader("a\nb\nc\nd\n"), BulkOrderCompletion, slowFirstLine)
+
+	lines := collectBulkResults(results)
+	assert.Len(t, lines, 4)
+	assert.Equal(t, lines[3], 1)
+}
+
+func TestStreamBulk_Concurrency(t *testing.T) {
+	for _, order := range []string{BulkOrderInput, BulkOrderCompletion} {
+		t.Run(order, func(t *testing.T) {
+			config.BulkConcurrency = 2
+			t.Cleanup(func() {
+				config = DefaultConfig()
+			})
+
+			var mutex sync.Mutex
+			running, maxRunning := 0, 0
+			process := func(_ context.Context, line bulkLine) BulkResult {
+				mutex.Lock()
+				running++
+				maxRunning = max(maxRunning, running)
+				mutex.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mutex.Lock()
+				running--
+				mutex.Unlock()
+				return BulkResult{Line: line.number}
+			}
+
+			body := strings.Repeat("line\n", 10)
+			results := streamBulk(context.Background(), strings.NewReader(body), order, process)
+
+			assert.Len(t, collectBulkResults(results), 10)
+			assert.Equal(t, maxRunning, 2)
+		})
+	}
+}
+
+func TestStreamBulk_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	body := strings.Repeat("line\n", 100)
+	results := streamBulk(ctx, strings.NewReader(body), BulkOrderInput, slowFirstLine)
+	cancel()
+
+	// the results are closed, instead of blocking forever on a client that went away
+	assert.Less(t, len(collectBulkResults(results)), 100)
+}
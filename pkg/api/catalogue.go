@@ -0,0 +1,567 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	goValidator "github.com/go-playground/validator/v10"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// Error codes are part of the public API contract. Once published, a code must never be renamed or reused.
+const (
+	CodeInternalError             = "internal_error"
+	CodeValidationError           = "validation_error"
+	CodeMalformedRequest          = "malformed_request"
+	CodeInvalidFlightLeg          = "invalid_flight_leg"
+	CodeUnknownFlightLegField     = "unknown_flight_leg_field"
+	CodeInvalidFlightLegFormat    = "invalid_flight_leg_format"
+	CodeInvalidFlightLegTime      = "invalid_flight_leg_time"
+	CodeInvalidCSVHeader          = "invalid_csv_header"
+	CodeInvalidCSVRow             = "invalid_csv_row"
+	CodeInvalidBulkOrder          = "invalid_bulk_order"
+	CodeInvalidReturnTrip         = "invalid_return_trip"
+	CodeInvalidDuplicatePolicy    = "invalid_duplicate_policy"
+	CodeInvalidRouteSearch        = "invalid_route_search"
+	CodeInvalidEmissionsOptions   = "invalid_emissions_options"
+	CodeMissingFragments          = "missing_fragments"
+	CodeMissingFragmentSource     = "missing_fragment_source"
+	CodeMissingFlightLegs         = "missing_flight_legs"
+	CodeMissingAirportCode        = "missing_airport_code"
+	CodeInvalidAirportCode        = "invalid_airport_code"
+	CodeEmptyFlightPath           = "empty_flight_path"
+	CodeDomainConflict            = "domain_conflict"
+	CodeSameDepartureAndArrival   = "same_departure_and_arrival"
+	CodeDuplicateFlightLeg        = "duplicate_flight_leg"
+	CodeUnknownRoute              = "unknown_route"
+	CodeMultipleOutboundLegs      = "multiple_outbound_legs"
+	CodeMultipleInboundLegs       = "multiple_inbound_legs"
+	CodeFlightPathLoop            = "flight_path_loop"
+	CodeDisconnectedFlightPath    = "disconnected_flight_path"
+	CodeMissingFlightLegTimes     = "missing_flight_leg_times"
+	CodeConflictingFlightLegs     = "conflicting_flight_legs"
+	CodeImplausibleSpeed          = "implausible_speed"
+	CodeOverlappingFlightLegs     = "overlapping_flight_legs"
+	CodeShortTurnaround           = "short_turnaround"
+	CodeMissingAirportCoordinates = "missing_airport_coordinates"
+	CodeTimeout                   = "timeout"
+	CodeRequestCanceled           = "request_canceled"
+	CodeStorageUnavailable        = "storage_unavailable"
+	CodeRequestTooLarge           = "request_too_large"
+	CodeTooManyFlightLegs         = "too_many_flight_legs"
+	CodeNotFound                  = "not_found"
+	CodeUnknownAirport            = "unknown_airport"
+	CodeRouteNotFound             = "route_not_found"
+	CodeNotImplemented            = "not_implemented"
+	CodeRouteNetworkUnavailable   = "route_network_unavailable"
+	CodeAirportsUnavailable       = "airports_unavailable"
+)
+
+type catalogueEntry struct {
+	class ErrorClass
+	// messages are keyed by language
+	messages map[string]string
+}
+
+// errorCatalogue holds the only error messages that are ever shown to clients, in every supported language. The
+// actual errors may contain internal details, such as type names or library messages, so they are only written to
+// the logs.
+var errorCatalogue = map[string]catalogueEntry{
+	CodeInternalError: {
+		ErrorClassInternal,
+		map[string]string{
+			"en": "An unexpected error occurred while processing the request.",
+			"es": "Ocurrió un error inesperado al procesar la solicitud.",
+			"pt": "Ocorreu um erro inesperado ao processar a requisição.",
+		},
+	},
+	CodeValidationError: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The request is invalid.",
+			"es": "La solicitud no es válida.",
+			"pt": "A requisição é inválida.",
+		},
+	},
+	CodeMalformedRequest: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The request body is not a valid JSON document, or it does not have the expected structure.",
+			"es": "El cuerpo de la solicitud no es un documento JSON válido, o no tiene la estructura esperada.",
+			"pt": "O corpo da requisição não é um documento JSON válido, ou não tem a estrutura esperada.",
+		},
+	},
+	CodeInvalidFlightLeg: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "Each flight leg must be a list with the departure and arrival airport codes, such as " +
+				`["SFO", "ORD"]; an object, such as {"departure": "SFO", "arrival": "ORD"}; or a string, such as "SFO-ORD".`,
+			"es": "Cada tramo de vuelo debe ser una lista con los códigos de aeropuerto de salida y llegada, como " +
+				`["SFO", "ORD"]; un objeto, como {"departure": "SFO", "arrival": "ORD"}; o un texto, como "SFO-ORD".`,
+			"pt": "Cada trecho de voo deve ser uma lista com os códigos de aeroporto de partida e chegada, como " +
+				`["SFO", "ORD"]; um objeto, como {"departure": "SFO", "arrival": "ORD"}; ou um texto, como "SFO-ORD".`,
+		},
+	},
+	CodeUnknownFlightLegField: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": `A flight leg object can only have the "departure" and "arrival" fields.`,
+			"es": `Un objeto de tramo de vuelo solo puede tener los campos "departure" y "arrival".`,
+			"pt": `Um objeto de trecho de voo só pode ter os campos "departure" e "arrival".`,
+		},
+	},
+	CodeInvalidFlightLegFormat: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": `The flight leg format must be "array", "object" or "string".`,
+			"es": `El formato de los tramos de vuelo debe ser "array", "object" o "string".`,
+			"pt": `O formato dos trechos de voo deve ser "array", "object" ou "string".`,
+		},
+	},
+	CodeInvalidFlightLegTime: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "Departure and arrival times must be in the RFC 3339 format, such as 2024-03-25T08:05:00-07:00.",
+			"es": "Las horas de salida y llegada deben estar en el formato RFC 3339, como 2024-03-25T08:05:00-07:00.",
+			"pt": "Os horários de partida e chegada devem estar no formato RFC 3339, como 2024-03-25T08:05:00-07:00.",
+		},
+	},
+	CodeInvalidCSVHeader: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The first CSV row must be a header with the departure and arrival columns, and optionally the " +
+				"departure_time and arrival_time columns.",
+			"es": "La primera fila del CSV debe ser un encabezado con las columnas departure y arrival, y " +
+				"opcionalmente las columnas departure_time y arrival_time.",
+			"pt": "A primeira linha do CSV deve ser um cabeçalho com as colunas departure e arrival, e " +
+				"opcionalmente as colunas departure_time e arrival_time.",
+		},
+	},
+	CodeInvalidCSVRow: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "Each CSV row must have one value for each column of the header.",
+			"es": "Cada fila del CSV debe tener un valor para cada columna del encabezado.",
+			"pt": "Cada linha do CSV deve ter um valor para cada coluna do cabeçalho.",
+		},
+	},
+	CodeInvalidBulkOrder: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The order of the results must be either input or completion.",
+			"es": "El orden de los resultados debe ser input o completion.",
+			"pt": "A ordem dos resultados deve ser input ou completion.",
+		},
+	},
+	CodeInvalidReturnTrip: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The return_trip parameter must be true or false, and the stay parameter must be a duration that " +
+				"is not negative, such as 72h, given only along with return_trip=true.",
+			"es": "El parámetro return_trip debe ser true o false, y el parámetro stay debe ser una duración que " +
+				"no sea negativa, como 72h, informada solo junto con return_trip=true.",
+			"pt": "O parâmetro return_trip deve ser true ou false, e o parâmetro stay deve ser uma duração que " +
+				"não seja negativa, como 72h, informada apenas junto com return_trip=true.",
+		},
+	},
+	CodeInvalidDuplicatePolicy: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The duplicates parameter must be reject, skip or warn.",
+			"es": "El parámetro duplicates debe ser reject, skip o warn.",
+			"pt": "O parâmetro duplicates deve ser reject, skip ou warn.",
+		},
+	},
+	CodeInvalidRouteSearch: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The from and to parameters must be different airport codes, and max_stops a number from 0 to 4.",
+			"es": "Los parámetros from y to deben ser códigos de aeropuerto distintos, y max_stops un número de 0 a 4.",
+			"pt": "Os parâmetros from e to devem ser códigos de aeroporto diferentes, e max_stops um número de 0 a 4.",
+		},
+	},
+	CodeInvalidEmissionsOptions: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The emissions parameter must be a boolean, and cabin_class one of the cabin classes of the emissions method, given along with emissions.",
+			"es": "El parámetro emissions debe ser un booleano, y cabin_class una de las clases de cabina del método de emisiones, informado junto con emissions.",
+			"pt": "O parâmetro emissions deve ser um booleano, e cabin_class uma das classes de cabine do método de emissões, informado junto com emissions.",
+		},
+	},
+	CodeMissingFragments: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "At least one fragment must be provided.",
+			"es": "Se debe informar al menos un fragmento.",
+			"pt": "Pelo menos um fragmento deve ser informado.",
+		},
+	},
+	CodeMissingFragmentSource: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "Each fragment must have a source, such as the airline or the travel agency that gave its flight legs.",
+			"es": "Cada fragmento debe tener un origen, como la aerolínea o la agencia de viajes que informó sus tramos de vuelo.",
+			"pt": "Cada fragmento deve ter uma origem, como a companhia aérea ou a agência de viagens que informou seus trechos de voo.",
+		},
+	},
+	CodeMissingFlightLegs: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "At least one flight leg must be provided.",
+			"es": "Se debe informar al menos un tramo de vuelo.",
+			"pt": "Pelo menos um trecho de voo deve ser informado.",
+		},
+	},
+	CodeMissingAirportCode: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The airport code cannot be empty.",
+			"es": "El código de aeropuerto no puede estar vacío.",
+			"pt": "O código de aeroporto não pode estar vazio.",
+		},
+	},
+	CodeInvalidAirportCode: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The airport code must be a 3-letter IATA airport code.",
+			"es": "El código de aeropuerto debe ser un código IATA de 3 letras.",
+			"pt": "O código de aeroporto deve ser um código IATA de 3 letras.",
+		},
+	},
+	CodeEmptyFlightPath: {
+		ErrorClassValidation,
+		map[string]string{
+			"en": "The flight path is empty.",
+			"es": "La ruta de vuelo está vacía.",
+			"pt": "A rota de voo está vazia.",
+		},
+	},
+	CodeDomainConflict: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The flight legs do not form a valid flight path.",
+			"es": "Los tramos de vuelo no forman una ruta de vuelo válida.",
+			"pt": "Os trechos de voo não formam uma rota de voo válida.",
+		},
+	},
+	CodeSameDepartureAndArrival: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "A flight leg cannot depart from and arrive at the same airport.",
+			"es": "Un tramo de vuelo no puede salir y llegar al mismo aeropuerto.",
+			"pt": "Um trecho de voo não pode partir e chegar no mesmo aeroporto.",
+		},
+	},
+	CodeDuplicateFlightLeg: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The same flight leg was given more than once.",
+			"es": "El mismo tramo de vuelo fue informado más de una vez.",
+			"pt": "O mesmo trecho de voo foi informado mais de uma vez.",
+		},
+	},
+	CodeUnknownRoute: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "No airline is known to fly nonstop between the airports of the flight leg.",
+			"es": "No se conoce ninguna aerolínea que vuele sin escalas entre los aeropuertos del tramo de vuelo.",
+			"pt": "Nenhuma companhia aérea é conhecida por voar sem escalas entre os aeroportos do trecho de voo.",
+		},
+	},
+	CodeMultipleOutboundLegs: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "An airport has more than one departing flight leg, so there is a branch or a loop in the flight path.",
+			"es": "Un aeropuerto tiene más de un tramo de vuelo de salida, por lo que hay una bifurcación o un ciclo en la ruta de vuelo.",
+			"pt": "Um aeroporto tem mais de um trecho de voo de partida, portanto há uma bifurcação ou um ciclo na rota de voo.",
+		},
+	},
+	CodeMultipleInboundLegs: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "An airport has more than one arriving flight leg, so there is a branch or a loop in the flight path.",
+			"es": "Un aeropuerto tiene más de un tramo de vuelo de llegada, por lo que hay una bifurcación o un ciclo en la ruta de vuelo.",
+			"pt": "Um aeroporto tem mais de um trecho de voo de chegada, portanto há uma bifurcação ou um ciclo na rota de voo.",
+		},
+	},
+	CodeFlightPathLoop: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The flight legs form a loop, so the flight path has no origin or destination.",
+			"es": "Los tramos de vuelo forman un ciclo, por lo que la ruta de vuelo no tiene origen ni destino.",
+			"pt": "Os trechos de voo formam um ciclo, portanto a rota de voo não tem origem nem destino.",
+		},
+	},
+	CodeDisconnectedFlightPath: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The flight legs do not form a single connected flight path.",
+			"es": "Los tramos de vuelo no forman una única ruta de vuelo conectada.",
+			"pt": "Os trechos de voo não formam uma única rota de voo conectada.",
+		},
+	},
+	CodeMissingFlightLegTimes: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "Every flight leg must have departure and arrival times to mirror the schedule of the return trip.",
+			"es": "Cada tramo de vuelo debe tener horas de salida y llegada para reflejar el horario del viaje de regreso.",
+			"pt": "Cada trecho de voo deve ter horários de partida e chegada para espelhar o horário da viagem de volta.",
+		},
+	},
+	CodeConflictingFlightLegs: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The sources contradict each other, since they give different flight legs departing from or arriving at the same airport.",
+			"es": "Los orígenes se contradicen, ya que informan tramos de vuelo diferentes que salen o llegan al mismo aeropuerto.",
+			"pt": "As origens se contradizem, pois informam trechos de voo diferentes que partem ou chegam no mesmo aeroporto.",
+		},
+	},
+	CodeMissingAirportCoordinates: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The emissions cannot be estimated, since the coordinates of one of the airports are unknown.",
+			"es": "No se pueden estimar las emisiones, ya que se desconocen las coordenadas de uno de los aeropuertos.",
+			"pt": "Não é possível estimar as emissões, pois as coordenadas de um dos aeroportos são desconhecidas.",
+		},
+	},
+	CodeImplausibleSpeed: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The flight leg would have been flown faster than an airliner can fly, or it arrives before it departs.",
+			"es": "El tramo de vuelo se habría volado más rápido de lo que puede volar un avión comercial, o llega antes de salir.",
+			"pt": "O trecho de voo teria sido voado mais rápido do que um avião comercial consegue voar, ou chega antes de partir.",
+		},
+	},
+	CodeOverlappingFlightLegs: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "A flight leg departs before the one before it arrives, so the traveler would be in two places at once.",
+			"es": "Un tramo de vuelo sale antes de que llegue el anterior, por lo que el viajero estaría en dos lugares a la vez.",
+			"pt": "Um trecho de voo parte antes que o anterior chegue, portanto o viajante estaria em dois lugares ao mesmo tempo.",
+		},
+	},
+	CodeShortTurnaround: {
+		ErrorClassDomainConflict,
+		map[string]string{
+			"en": "The connection between the flight legs is too short to be made.",
+			"es": "La conexión entre los tramos de vuelo es demasiado corta para realizarse.",
+			"pt": "A conexão entre os trechos de voo é curta demais para ser feita.",
+		},
+	},
+	CodeTimeout: {
+		ErrorClassTimeout,
+		map[string]string{
+			"en": "The request took too long to be processed. Please try again later.",
+			"es": "La solicitud tardó demasiado en procesarse. Por favor, inténtelo de nuevo más tarde.",
+			"pt": "A requisição demorou demais para ser processada. Por favor, tente novamente mais tarde.",
+		},
+	},
+	CodeRequestCanceled: {
+		ErrorClassCanceled,
+		map[string]string{
+			"en": "The request was canceled before it could be processed. Please try again.",
+			"es": "La solicitud fue cancelada antes de que pudiera procesarse. Por favor, inténtelo de nuevo.",
+			"pt": "A requisição foi cancelada antes que pudesse ser processada. Por favor, tente novamente.",
+		},
+	},
+	CodeStorageUnavailable: {
+		ErrorClassStorageUnavailable,
+		map[string]string{
+			"en": "The service is temporarily unavailable. Please try again later.",
+			"es": "El servicio no está disponible temporalmente. Por favor, inténtelo de nuevo más tarde.",
+			"pt": "O serviço está temporariamente indisponível. Por favor, tente novamente mais tarde.",
+		},
+	},
+	CodeRequestTooLarge: {
+		ErrorClassRequestTooLarge,
+		map[string]string{
+			"en": "The request body is too large.",
+			"es": "El cuerpo de la solicitud es demasiado grande.",
+			"pt": "O corpo da requisição é grande demais.",
+		},
+	},
+	CodeTooManyFlightLegs: {
+		ErrorClassRequestTooLarge,
+		map[string]string{
+			"en": "The flight path has too many flight legs.",
+			"es": "La ruta de vuelo tiene demasiados tramos.",
+			"pt": "A rota de voo tem trechos demais.",
+		},
+	},
+	CodeNotFound: {
+		ErrorClassNotFound,
+		map[string]string{
+			"en": "The requested resource was not found.",
+			"es": "No se encontró el recurso solicitado.",
+			"pt": "O recurso solicitado não foi encontrado.",
+		},
+	},
+	CodeUnknownAirport: {
+		ErrorClassNotFound,
+		map[string]string{
+			"en": "The airport is not known to the route network.",
+			"es": "La red de rutas no conoce el aeropuerto.",
+			"pt": "A rede de rotas não conhece o aeroporto.",
+		},
+	},
+	CodeRouteNotFound: {
+		ErrorClassNotFound,
+		map[string]string{
+			"en": "No route was found between the airports with up to the given number of stops.",
+			"es": "No se encontró ninguna ruta entre los aeropuertos con hasta la cantidad de escalas indicada.",
+			"pt": "Nenhuma rota foi encontrada entre os aeroportos com até a quantidade de escalas informada.",
+		},
+	},
+	CodeAirportsUnavailable: {
+		ErrorClassNotImplemented,
+		map[string]string{
+			"en": "The server has no airports dataset to estimate emissions with.",
+			"es": "El servidor no tiene un conjunto de datos de aeropuertos con el cual estimar las emisiones.",
+			"pt": "O servidor não tem um conjunto de dados de aeroportos com o qual estimar as emissões.",
+		},
+	},
+	CodeNotImplemented: {
+		ErrorClassNotImplemented,
+		map[string]string{
+			"en": "The server is not configured to support this request.",
+			"es": "El servidor no está configurado para admitir esta solicitud.",
+			"pt": "O servidor não está configurado para atender esta requisição.",
+		},
+	},
+	CodeRouteNetworkUnavailable: {
+		ErrorClassNotImplemented,
+		map[string]string{
+			"en": "The server has no route network to search.",
+			"es": "El servidor no tiene una red de rutas para buscar.",
+			"pt": "O servidor não tem uma rede de rotas para pesquisar.",
+		},
+	},
+}
+
+// PublicMessage is the message of the error code in the given language, or in English if there is no translation.
+func PublicMessage(code, language string) string {
+	messages := errorCatalogue[code].messages
+	if message, ok := messages[language]; ok {
+		return message
+	}
+	return messages[defaultLanguage]
+}
+
+// ErrorCode finds the catalogue code that best describes the error.
+func ErrorCode(err error) string {
+	var classified *classifiedError
+	if errors.As(err, &classified) && classified.class == ErrorClassValidation {
+		return validationErrorCode(classified.err)
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeRequestCanceled
+	case errors.Is(err, ErrStorageUnavailable):
+		return CodeStorageUnavailable
+	case errors.Is(err, ErrRouteNetworkUnavailable):
+		return CodeRouteNetworkUnavailable
+	case errors.Is(err, ErrAirportsUnavailable):
+		return CodeAirportsUnavailable
+	case errors.Is(err, domain.ErrEmissionsWithoutAirport):
+		return CodeMissingAirportCoordinates
+	case errors.Is(err, domain.ErrUnknownAirport):
+		return CodeUnknownAirport
+	case errors.Is(err, domain.ErrRouteNotFound):
+		return CodeRouteNotFound
+	case errors.Is(err, domain.ErrEmptyFlightPath):
+		return CodeEmptyFlightPath
+	case errors.Is(err, domain.ErrConflictingFlightLegs):
+		return CodeConflictingFlightLegs
+	case errors.Is(err, domain.ErrDuplicateFlightLeg):
+		return CodeDuplicateFlightLeg
+	case errors.Is(err, domain.ErrUnknownRoute):
+		return CodeUnknownRoute
+	case errors.Is(err, domain.ErrSamePoints):
+		return CodeSameDepartureAndArrival
+	case errors.Is(err, domain.ErrOutboundConnectionExists):
+		return CodeMultipleOutboundLegs
+	case errors.Is(err, domain.ErrInboundConnectionExists):
+		return CodeMultipleInboundLegs
+	case errors.Is(err, domain.ErrStartNotFound), errors.Is(err, domain.ErrEndNotFound):
+		return CodeFlightPathLoop
+	case errors.Is(err, domain.ErrImplausibleSpeed):
+		return CodeImplausibleSpeed
+	case errors.Is(err, domain.ErrOverlappingFlightLegs):
+		return CodeOverlappingFlightLegs
+	case errors.Is(err, domain.ErrShortTurnaround):
+		return CodeShortTurnaround
+	case errors.Is(err, domain.ErrDisconnectedFlightPath):
+		return CodeDisconnectedFlightPath
+	case errors.Is(err, domain.ErrMissingFlightLegTimes):
+		return CodeMissingFlightLegTimes
+	case errors.Is(err, domain.ErrInvalidFlightPath):
+		return CodeDomainConflict
+	default:
+		return CodeInternalError
+	}
+}
+
+func validationErrorCode(err error) string {
+	var errs fieldErrors
+	var validationErrors goValidator.ValidationErrors
+	var syntaxError *json.SyntaxError
+	var unmarshalTypeError *json.UnmarshalTypeError
+	var csvParseError *csv.ParseError
+	var maxBytesError *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &errs) && len(errs) > 0:
+		return errs[0].code
+	case errors.Is(err, model.ErrUnknownFlightLegField):
+		return CodeUnknownFlightLegField
+	case errors.Is(err, model.ErrInvalidFlightLeg):
+		return CodeInvalidFlightLeg
+	case errors.Is(err, ErrInvalidBulkOrder):
+		return CodeInvalidBulkOrder
+	case errors.Is(err, ErrInvalidReturnTrip):
+		return CodeInvalidReturnTrip
+	case errors.Is(err, ErrInvalidDuplicatePolicy):
+		return CodeInvalidDuplicatePolicy
+	case errors.Is(err, ErrInvalidRouteSearch):
+		return CodeInvalidRouteSearch
+	case errors.Is(err, ErrInvalidEmissionsOptions):
+		return CodeInvalidEmissionsOptions
+	case errors.As(err, &maxBytesError):
+		return CodeRequestTooLarge
+	case errors.Is(err, ErrTooManyFlightLegs):
+		return CodeTooManyFlightLegs
+	case errors.As(err, &syntaxError), errors.As(err, &unmarshalTypeError), errors.As(err, &csvParseError),
+		errors.Is(err, errUnexpectedJSONToken),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, bufio.ErrTooLong):
+		return CodeMalformedRequest
+	case errors.As(err, &validationErrors) && len(validationErrors) > 0:
+		return fieldErrorCode(validationErrors[0])
+	default:
+		return CodeValidationError
+	}
+}
+
+func fieldErrorCode(fieldError goValidator.FieldError) string {
+	switch {
+	case fieldError.StructField() == "FlightLegFormat":
+		return CodeInvalidFlightLegFormat
+	case fieldError.Tag() == "airport_code":
+		return CodeInvalidAirportCode
+	case fieldError.StructField() == "FlightLegs":
+		return CodeMissingFlightLegs
+	case fieldError.Tag() == "required":
+		return CodeMissingAirportCode
+	default:
+		return CodeValidationError
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+	"github.com/felipead/flight-path-tracker/pkg/validator"
+)
+
+func TestErrorCatalogue_EveryEntryHasAMessageInEverySupportedLanguage(t *testing.T) {
+	for code, entry := range errorCatalogue {
+		for _, language := range []string{"en", "es", "pt"} {
+			assert.NotEmpty(t, entry.messages[language], "code %v has no message in %v", code, language)
+		}
+	}
+}
+
+func TestPublicMessage(t *testing.T) {
+	assert.Equal(t, PublicMessage(CodeFlightPathLoop, "en"),
+		"The flight legs form a loop, so the flight path has no origin or destination.")
+	assert.Equal(t, PublicMessage(CodeFlightPathLoop, "es"),
+		"Los tramos de vuelo forman un ciclo, por lo que la ruta de vuelo no tiene origen ni destino.")
+	assert.Equal(t, PublicMessage(CodeFlightPathLoop, "pt"),
+		"Os trechos de voo formam um ciclo, portanto a rota de voo não tem origem nem destino.")
+	assert.Equal(t, PublicMessage(CodeFlightPathLoop, "de"),
+		"The flight legs form a loop, so the flight path has no origin or destination.")
+}
+
+func TestErrorCode_DecodingErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantCode string
+	}{
+		{
+			name:     "invalid JSON",
+			payload:  `{"flight_legs": [`,
+			wantCode: CodeMalformedRequest,
+		},
+		{
+			name:     "flight legs is not a list",
+			payload:  `{"flight_legs": 333}`,
+			wantCode: CodeMalformedRequest,
+		},
+		{
+			name:     "flight leg is a number",
+			payload:  `{"flight_legs": [5]}`,
+			wantCode: CodeInvalidFlightLeg,
+		},
+		{
+			name:     "flight leg with a single airport",
+			payload:  `{"flight_legs": [["SFO"]]}`,
+			wantCode: CodeInvalidFlightLeg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request CalculateFlightPathRequest
+			err := json.Unmarshal([]byte(tt.payload), &request)
+			assert.Error(t, err)
+			assert.Equal(t, ErrorCode(NewValidationError(err)), tt.wantCode)
+		})
+	}
+}
+
+func TestErrorCode_ValidationErrors(t *testing.T) {
+	assert.NoError(t, validator.InitValidator())
+
+	tests := []struct {
+		name       string
+		flightLegs []model.FlightLeg
+		wantCode   string
+	}{
+		{
+			name:       "no flight legs",
+			flightLegs: []model.FlightLeg{},
+			wantCode:   CodeMissingFlightLegs,
+		},
+		{
+			name:       "empty airport code",
+			flightLegs: []model.FlightLeg{{Departure: "", Arrival: "ORD"}},
+			wantCode:   CodeMissingAirportCode,
+		},
+		{
+			name:       "invalid airport code",
+			flightLegs: []model.FlightLeg{{Departure: "SFO", Arrival: "O5D"}},
+			wantCode:   CodeInvalidAirportCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.GetValidator().Struct(&CalculateFlightPathRequest{FlightLegs: tt.flightLegs})
+			assert.Error(t, err)
+			assert.Equal(t, ErrorCode(NewValidationError(err)), tt.wantCode)
+		})
+	}
+}
+
+func TestErrorCode_DomainErrors(t *testing.T) {
+	tests := []struct {
+		err      error
+		wantCode string
+	}{
+		{domain.ErrEmptyFlightPath, CodeEmptyFlightPath},
+		{fmt.Errorf("%w XYZ", domain.ErrUnknownAirport), CodeUnknownAirport},
+		{fmt.Errorf("%w of 1553 km/h from SFO to DEN", domain.ErrImplausibleSpeed), CodeImplausibleSpeed},
+		{fmt.Errorf("%w; the flight leg from DEN to ORD departs 30m0s before", domain.ErrOverlappingFlightLegs),
+			CodeOverlappingFlightLegs},
+		{fmt.Errorf("%w of 5m0s at ORD", domain.ErrShortTurnaround), CodeShortTurnaround},
+		{fmt.Errorf("%w from GSO to SFO with up to 2 stops", domain.ErrRouteNotFound), CodeRouteNotFound},
+		{ErrRouteNetworkUnavailable, CodeRouteNetworkUnavailable},
+		{ErrAirportsUnavailable, CodeAirportsUnavailable},
+		{fmt.Errorf("%w XXX", domain.ErrEmissionsWithoutAirport), CodeMissingAirportCoordinates},
+		{fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrSamePoints), CodeSameDepartureAndArrival},
+		{fmt.Errorf("%w; %w from SFO to ATL", domain.ErrInvalidFlightPath, domain.ErrDuplicateFlightLeg), CodeDuplicateFlightLeg},
+		{fmt.Errorf("%w; %w from GSO to IND", domain.ErrInvalidFlightPath, domain.ErrUnknownRoute), CodeUnknownRoute},
+		{fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrOutboundConnectionExists), CodeMultipleOutboundLegs},
+		{fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrInboundConnectionExists), CodeMultipleInboundLegs},
+		{fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrStartNotFound), CodeFlightPathLoop},
+		{fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrEndNotFound), CodeFlightPathLoop},
+		{fmt.Errorf("%w; no flight leg leaving IND", domain.ErrDisconnectedFlightPath), CodeDisconnectedFlightPath},
+		{fmt.Errorf("%w; the flight leg from SFO to ATL has none", domain.ErrMissingFlightLegTimes), CodeMissingFlightLegTimes},
+		{&domain.MergeConflictError{Conflicts: []domain.FlightLegConflict{{Airport: "SFO"}}}, CodeConflictingFlightLegs},
+		{domain.ErrInvalidFlightPath, CodeDomainConflict},
+		{errors.New("boom"), CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantCode, func(t *testing.T) {
+			assert.Equal(t, ErrorCode(tt.err), tt.wantCode)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+const (
+	calculationTimeoutEnv    = "CALCULATION_TIMEOUT"
+	strictFlightLegFieldsEnv = "STRICT_FLIGHT_LEG_FIELDS"
+	bulkConcurrencyEnv       = "BULK_CONCURRENCY"
+	maxRequestSizeEnv        = "MAX_REQUEST_SIZE"
+	maxFlightLegsEnv         = "MAX_FLIGHT_LEGS"
+	streamingThresholdEnv    = "STREAMING_THRESHOLD"
+	routesFileEnv            = "ROUTES_FILE"
+	strictRoutesEnv          = "STRICT_ROUTES"
+	airportsFileEnv          = "AIRPORTS_FILE"
+	emissionsMethodFileEnv   = "EMISSIONS_METHOD_FILE"
+)
+
+type Config struct {
+	// CalculationTimeout is the maximum amount of time a flight path calculation can take before it is aborted.
+	CalculationTimeout time.Duration
+	// StrictFlightLegFields rejects flight legs in the object format that have unknown fields, instead of ignoring them.
+	StrictFlightLegFields bool
+	// BulkConcurrency is the maximum number of lines of a bulk request that are calculated at the same time.
+	BulkConcurrency int
+	// MaxRequestSize is the size in bytes of the largest request body accepted when calculating a single flight path.
+	MaxRequestSize int64
+	// MaxFlightLegs is the maximum number of flight legs of a single flight path.
+	MaxFlightLegs int
+	// StreamingThreshold is the size in bytes above which JSON request bodies are decoded as a stream, instead of
+	// being read into memory as a whole. Bodies of unknown size are always decoded as a stream.
+	StreamingThreshold int64
+	// RoutesFile is the path of a route network dataset, in the format of the routes.dat file of OpenFlights. If
+	// given, flight legs whose route is not in the dataset are flagged with a warning.
+	RoutesFile string
+	// StrictRoutes rejects flight legs whose route is not in the dataset of RoutesFile, instead of flagging them.
+	StrictRoutes bool
+	// AirportsFile is the path of an airports dataset, in the format of the airports.dat file of OpenFlights. Along
+	// with RoutesFile, it is needed to search for routes between airports.
+	AirportsFile string
+	// EmissionsMethodFile is the path of a JSON file with the method of emissions estimates. If not given, the
+	// default method is used.
+	EmissionsMethodFile string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		CalculationTimeout: 5 * time.Second,
+		BulkConcurrency:    runtime.NumCPU(),
+		MaxRequestSize:     64 * 1024 * 1024,
+		MaxFlightLegs:      1_000_000,
+		StreamingThreshold: 1024 * 1024,
+	}
+}
+
+// LoadConfig starts from DefaultConfig and overrides any settings given through environment variables.
+func LoadConfig() (Config, error) {
+	config := DefaultConfig()
+
+	if value, ok := os.LookupEnv(calculationTimeoutEnv); ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %v: %w", calculationTimeoutEnv, err)
+		}
+		if timeout <= 0 {
+			return config, fmt.Errorf("invalid %v: must be positive", calculationTimeoutEnv)
+		}
+		config.CalculationTimeout = timeout
+	}
+
+	if value, ok := os.LookupEnv(strictFlightLegFieldsEnv); ok {
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %v: %w", strictFlightLegFieldsEnv, err)
+		}
+		config.StrictFlightLegFields = strict
+	}
+
+	if value, ok := os.LookupEnv(bulkConcurrencyEnv); ok {
+		concurrency, err := parsePositiveInt(bulkConcurrencyEnv, value)
+		if err != nil {
+			return config, err
+		}
+		config.BulkConcurrency = int(concurrency)
+	}
+
+	if value, ok := os.LookupEnv(maxRequestSizeEnv); ok {
+		size, err := parsePositiveInt(maxRequestSizeEnv, value)
+		if err != nil {
+			return config, err
+		}
+		config.MaxRequestSize = size
+	}
+
+	if value, ok := os.LookupEnv(maxFlightLegsEnv); ok {
+		count, err := parsePositiveInt(maxFlightLegsEnv, value)
+		if err != nil {
+			return config, err
+		}
+		config.MaxFlightLegs = int(count)
+	}
+
+	if value, ok := os.LookupEnv(streamingThresholdEnv); ok {
+		size, err := parsePositiveInt(streamingThresholdEnv, value)
+		if err != nil {
+			return config, err
+		}
+		config.StreamingThreshold = size
+	}
+
+	if value, ok := os.LookupEnv(routesFileEnv); ok {
+		config.RoutesFile = value
+	}
+
+	if value, ok := os.LookupEnv(strictRoutesEnv); ok {
+		strict, err := strconv.ParseBool(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %v: %w", strictRoutesEnv, err)
+		}
+		config.StrictRoutes = strict
+	}
+
+	if value, ok := os.LookupEnv(airportsFileEnv); ok {
+		config.AirportsFile = value
+	}
+
+	if value, ok := os.LookupEnv(emissionsMethodFileEnv); ok {
+		config.EmissionsMethodFile = value
+	}
+
+	return config, nil
+}
+
+func parsePositiveInt(env, value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %w", env, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid %v: must be positive", env)
+	}
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config, DefaultConfig())
+}
+
+func TestLoadConfig_CalculationTimeout(t *testing.T) {
+	t.Setenv("CALCULATION_TIMEOUT", "250ms")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.CalculationTimeout, 250*time.Millisecond)
+}
+
+func TestLoadConfig_InvalidCalculationTimeout(t *testing.T) {
+	t.Setenv("CALCULATION_TIMEOUT", "soon")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "invalid CALCULATION_TIMEOUT")
+}
+
+func TestLoadConfig_NegativeCalculationTimeout(t *testing.T) {
+	t.Setenv("CALCULATION_TIMEOUT", "-1s")
+
+	_, err := LoadConfig()
+	assert.EqualError(t, err, "invalid CALCULATION_TIMEOUT: must be positive")
+}
+
+func TestLoadConfig_BulkConcurrency(t *testing.T) {
+	t.Setenv("BULK_CONCURRENCY", "16")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.BulkConcurrency, 16)
+}
+
+func TestLoadConfig_InvalidBulkConcurrency(t *testing.T) {
+	t.Setenv("BULK_CONCURRENCY", "0")
+
+	_, err := LoadConfig()
+	assert.EqualError(t, err, "invalid BULK_CONCURRENCY: must be positive")
+}
+
+func TestLoadConfig_RequestLimits(t *testing.T) {
+	t.Setenv("MAX_REQUEST_SIZE", "1048576")
+	t.Setenv("MAX_FLIGHT_LEGS", "500")
+	t.Setenv("STREAMING_THRESHOLD", "4096")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.MaxRequestSize, int64(1048576))
+	assert.Equal(t, config.MaxFlightLegs, 500)
+	assert.Equal(t, config.StreamingThreshold, int64(4096))
+}
+
+func TestLoadConfig_InvalidMaxFlightLegs(t *testing.T) {
+	t.Setenv("MAX_FLIGHT_LEGS", "many")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "invalid MAX_FLIGHT_LEGS")
+}
+
+func TestLoadConfig_Routes(t *testing.T) {
+	t.Setenv("ROUTES_FILE", "data/routes.dat")
+	t.Setenv("STRICT_ROUTES", "true")
+	t.Setenv("AIRPORTS_FILE", "data/airports.dat")
+	t.Setenv("EMISSIONS_METHOD_FILE", "data/emissions_method.json")
+
+	config, err := LoadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.RoutesFile, "data/routes.dat")
+	assert.True(t, config.StrictRoutes)
+	assert.Equal(t, config.AirportsFile, "data/airports.dat")
+	assert.Equal(t, config.EmissionsMethodFile, "data/emissions_method.json")
+}
+
+func TestLoadConfig_InvalidStrictRoutes(t *testing.T) {
+	t.Setenv("STRICT_ROUTES", "sometimes")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "invalid STRICT_ROUTES")
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	correlationIDKey = "correlation_id"
+)
+
+// Correlation IDs given by clients are echoed back and written to the logs, so anything other than a short
+// token is discarded to avoid log injection.
+var validCorrelationID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// CorrelationID is a middleware that assigns an identifier to every request, so that errors shown to clients can be
+// linked to the internal details in the logs. A valid X-Correlation-ID request header is honored, otherwise a new
+// identifier is generated.
+func CorrelationID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		getCorrelationID(c)
+		c.Next()
+	}
+}
+
+func getCorrelationID(c *gin.Context) string {
+	if id := c.GetString(correlationIDKey); id != "" {
+		return id
+	}
+
+	id := c.GetHeader(CorrelationIDHeader)
+	if !validCorrelationID.MatchString(id) {
+		id = newCorrelationID()
+	}
+
+	c.Set(correlationIDKey, id)
+	c.Header(CorrelationIDHeader, id)
+	return id
+}
+
+func newCorrelationID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
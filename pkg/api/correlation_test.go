@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithCorrelationID(header string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CorrelationID())
+	router.GET("/", func(c *gin.Context) {
+		c.String(200, getCorrelationID(c))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		request.Header.Set(CorrelationIDHeader, header)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCorrelationID_Generated(t *testing.T) {
+	response := serveWithCorrelationID("")
+
+	id := response.Header().Get(CorrelationIDHeader)
+	assert.Len(t, id, 32)
+	assert.Equal(t, response.Body.String(), id)
+}
+
+func TestCorrelationID_GivenByClient(t *testing.T) {
+	response := serveWithCorrelationID("req-42.a_b")
+
+	assert.Equal(t, response.Header().Get(CorrelationIDHeader), "req-42.a_b")
+	assert.Equal(t, response.Body.String(), "req-42.a_b")
+}
+
+func TestCorrelationID_InvalidValueGivenByClientIsReplaced(t *testing.T) {
+	response := serveWithCorrelationID("foo\nlevel=error msg=forged")
+
+	id := response.Header().Get(CorrelationIDHeader)
+	assert.Len(t, id, 32)
+	assert.NotContains(t, id, "forged")
+}
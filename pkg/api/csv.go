@@ -0,0 +1,228 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const MIMECSV = "text/csv"
+
+const (
+	csvDepartureColumn     = "departure"
+	csvArrivalColumn       = "arrival"
+	csvDepartureTimeColumn = "departure_time"
+	csvArrivalTimeColumn   = "arrival_time"
+)
+
+var csvColumns = []string{csvDepartureColumn, csvArrivalColumn, csvDepartureTimeColumn, csvArrivalTimeColumn}
+
+var (
+	errMissingCSVHeader   = errors.New("missing CSV header")
+	errDuplicateCSVColumn = errors.New("duplicate CSV column")
+	errUnknownCSVColumn   = errors.New("unknown CSV column")
+	errMissingCSVColumn   = errors.New("missing CSV column")
+	errCSVRowLength       = errors.New("CSV row does not have one value for each column")
+)
+
+// UnmarshalCSV decodes the flight legs from a CSV document, where the first row is a header naming the columns.
+// The departure and arrival columns are required, while the departure_time and arrival_time columns are optional.
+// Like UnmarshalJSON, it goes through all rows, so that the errors of all invalid rows are returned together.
+func (r *CalculateFlightPathRequest) UnmarshalCSV(body io.Reader) error {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return fieldErrors{newCSVFieldError(1, "", -1, CodeInvalidCSVHeader, nil, errMissingCSVHeader)}
+	}
+	if err != nil {
+		return err
+	}
+
+	columns, errs := parseCSVHeader(header)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	r.FlightLegs = []model.FlightLeg{}
+	r.flightLegFormats = nil
+	r.csvRows = []int{}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// the reader cannot recover from malformed CSV, such as a bare quote
+			return err
+		}
+
+		row, _ := reader.FieldPos(0)
+		index := len(r.FlightLegs)
+
+		leg, legErrs := parseCSVFlightLeg(record, len(header), columns, row, index)
+		errs = append(errs, legErrs...)
+
+		r.FlightLegs = append(r.FlightLegs, leg)
+		r.csvRows = append(r.csvRows, row)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// parseCSVHeader finds the index of each known column
+func parseCSVHeader(header []string) (map[string]int, fieldErrors) {
+	var errs fieldErrors
+	columns := make(map[string]int, len(header))
+
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheets often start their CSV exports with a UTF-8 byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		switch {
+		case !isCSVColumn(name):
+			if config.StrictFlightLegFields {
+				errs = append(errs, newCSVFieldError(1, name, -1, CodeInvalidCSVHeader, name, errUnknownCSVColumn))
+			}
+		case hasCSVColumn(columns, name):
+			errs = append(errs, newCSVFieldError(1, name, -1, CodeInvalidCSVHeader, name, errDuplicateCSVColumn))
+		default:
+			columns[name] = i
+		}
+	}
+
+	for _, name := range []string{csvDepartureColumn, csvArrivalColumn} {
+		if !hasCSVColumn(columns, name) {
+			errs = append(errs, newCSVFieldError(1, name, -1, CodeInvalidCSVHeader, nil, errMissingCSVColumn))
+		}
+	}
+
+	return columns, errs
+}
+
+func parseCSVFlightLeg(record []string, width int, columns map[string]int, row, index int) (model.FlightLeg, fieldErrors) {
+	var errs fieldErrors
+	var leg model.FlightLeg
+
+	if len(record) != width {
+		errs = append(errs, newCSVFieldError(row, "", index, CodeInvalidCSVRow, nil, errCSVRowLength))
+		return leg, errs
+	}
+
+	leg.Departure = model.AirportCode(record[columns[csvDepartureColumn]])
+	leg.Arrival = model.AirportCode(record[columns[csvArrivalColumn]])
+
+	var err *fieldError
+	if leg.DepartureTime, err = parseCSVTime(record, columns, csvDepartureTimeColumn, row, index); err != nil {
+		errs = append(errs, err)
+	}
+	if leg.ArrivalTime, err = parseCSVTime(record, columns, csvArrivalTimeColumn, row, index); err != nil {
+		errs = append(errs, err)
+	}
+
+	return leg, errs
+}
+
+func parseCSVTime(record []string, columns map[string]int, column string, row, index int) (*time.Time, *fieldError) {
+	i, ok := columns[column]
+	if !ok || record[i] == "" {
+		return nil, nil
+	}
+
+	t, err := model.ParseFlightLegTime(record[i])
+	if err != nil {
+		return nil, newCSVFieldError(row, column, index, CodeInvalidFlightLegTime, record[i],
+			fmt.Errorf("%w: %w", model.ErrInvalidFlightLegTime, err))
+	}
+	return &t, nil
+}
+
+func newCSVFieldError(row int, column string, leg int, code string, value interface{}, err error) *fieldError {
+	return &fieldError{
+		location: FieldLocation{Row: row, Column: column},
+		leg:      leg,
+		code:     code,
+		value:    value,
+		err:      fmt.Errorf("row %v: %w", row, err),
+	}
+}
+
+func isCSVColumn(name string) bool {
+	return slices.Contains(csvColumns, name)
+}
+
+// csvColumnRank orders the known columns as they are listed in csvColumns, followed by the unknown ones. Errors that
+// are not located by a column, such as those of a whole row, come first.
+func csvColumnRank(name string) int {
+	if name == "" {
+		return -1
+	}
+	if i := slices.Index(csvColumns, name); i >= 0 {
+		return i
+	}
+	return len(csvColumns)
+}
+
+func hasCSVColumn(columns map[string]int, name string) bool {
+	_, ok := columns[name]
+	return ok
+}
+
+// MarshalFlightPathCSV encodes the sorted flight legs as CSV, with a header row. The time columns are only present if
+// at least one flight leg has a departure or arrival time.
+func MarshalFlightPathCSV(flightPath *model.FlightPath) ([]byte, error) {
+	withTimes := false
+	for _, leg := range flightPath.FlightLegs {
+		if leg.DepartureTime != nil || leg.ArrivalTime != nil {
+			withTimes = true
+			break
+		}
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{csvDepartureColumn, csvArrivalColumn}
+	if withTimes {
+		header = append(header, csvDepartureTimeColumn, csvArrivalTimeColumn)
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, leg := range flightPath.FlightLegs {
+		record := []string{string(leg.Departure), string(leg.Arrival)}
+		if withTimes {
+			record = append(record, formatCSVTime(leg.DepartureTime), formatCSVTime(leg.ArrivalTime))
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
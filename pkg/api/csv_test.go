@@ -0,0 +1,154 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestCalculateFlightPathRequest_UnmarshalCSV(t *testing.T) {
+	body := "\ufeffDeparture, Arrival\nSFO,ATL\r\nATL,EWR\n"
+
+	var request CalculateFlightPathRequest
+	assert.NoError(t, request.UnmarshalCSV(strings.NewReader(body)))
+
+	assert.Equal(t, request.FlightLegs, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "ATL", Arrival: "EWR"},
+	})
+	assert.Equal(t, request.csvRows, []int{2, 3})
+}
+
+func TestCalculateFlightPathRequest_UnmarshalCSV_Times(t *testing.T) {
+	body := "arrival_time,departure,arrival,departure_time\n" +
+		"2024-03-25T10:30:00Z,SFO,ATL,2024-03-25T08:05:00Z\n" +
+		",ATL,EWR,\n"
+
+	var request CalculateFlightPathRequest
+	assert.NoError(t, request.UnmarshalCSV(strings.NewReader(body)))
+
+	departureTime := time.Date(2024, 3, 25, 8, 5, 0, 0, time.UTC)
+	arrivalTime := time.Date(2024, 3, 25, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, request.FlightLegs, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL", DepartureTime: &departureTime, ArrivalTime: &arrivalTime},
+		{Departure: "ATL", Arrival: "EWR"},
+	})
+}
+
+func TestCalculateFlightPathRequest_UnmarshalCSV_InvalidHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		strict   bool
+		wantErrs []FieldLocation
+	}{
+		{"empty", "", false, []FieldLocation{{Row: 1}}},
+		{"missing column", "departure,gate\nSFO,A1\n", false, []FieldLocation{{Row: 1, Column: "arrival"}}},
+		{"duplicate column", "departure,arrival,arrival\n", false, []FieldLocation{{Row: 1, Column: "arrival"}}},
+		{"unknown column", "departure,arrival,gate\n", true, []FieldLocation{{Row: 1, Column: "gate"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.StrictFlightLegFields = tt.strict
+			t.Cleanup(func() {
+				config = DefaultConfig()
+			})
+
+			var request CalculateFlightPathRequest
+			err := request.UnmarshalCSV(strings.NewReader(tt.body))
+
+			var errs fieldErrors
+			assert.True(t, errors.As(err, &errs))
+
+			var locations []FieldLocation
+			for _, e := range errs {
+				assert.Equal(t, e.code, CodeInvalidCSVHeader)
+				locations = append(locations, e.location)
+			}
+			assert.Equal(t, locations, tt.wantErrs)
+		})
+	}
+}
+
+func TestCalculateFlightPathRequest_UnmarshalCSV_UnknownColumnsAreIgnored(t *testing.T) {
+	var request CalculateFlightPathRequest
+	assert.NoError(t, request.UnmarshalCSV(strings.NewReader("gate,departure,arrival\nA1,SFO,ATL\n")))
+
+	assert.Equal(t, request.FlightLegs, []model.FlightLeg{{Departure: "SFO", Arrival: "ATL"}})
+}
+
+func TestCalculateFlightPathRequest_UnmarshalCSV_AggregatedErrors(t *testing.T) {
+	body := "departure,arrival,departure_time\n" +
+		"SFO,ATL,\n" +
+		"ATL\n" +
+		"EWR,IND,yesterday\n"
+
+	var request CalculateFlightPathRequest
+	err := request.UnmarshalCSV(strings.NewReader(body))
+
+	var errs fieldErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 2)
+
+	assert.Equal(t, errs[0].location, FieldLocation{Row: 3})
+	assert.Equal(t, errs[0].leg, 1)
+	assert.Equal(t, errs[0].code, CodeInvalidCSVRow)
+
+	assert.Equal(t, errs[1].location, FieldLocation{Row: 4, Column: "departure_time"})
+	assert.Equal(t, errs[1].leg, 2)
+	assert.Equal(t, errs[1].code, CodeInvalidFlightLegTime)
+	assert.Equal(t, errs[1].value, "yesterday")
+	assert.ErrorIs(t, errs[1], model.ErrInvalidFlightLegTime)
+
+	// the legs are kept, so that their rows can still be located
+	assert.Equal(t, request.csvRows, []int{2, 3, 4})
+}
+
+func TestCalculateFlightPathRequest_UnmarshalCSV_Malformed(t *testing.T) {
+	var request CalculateFlightPathRequest
+	err := request.UnmarshalCSV(strings.NewReader("departure,arrival\n\"SFO,ATL\n"))
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrorCode(NewValidationError(err)), CodeMalformedRequest)
+}
+
+func TestMarshalFlightPathCSV(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "EWR",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "EWR"},
+		},
+	}
+
+	body, err := MarshalFlightPathCSV(flightPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(body), "departure,arrival\nSFO,ATL\nATL,EWR\n")
+}
+
+func TestMarshalFlightPathCSV_Times(t *testing.T) {
+	departureTime := time.Date(2024, 3, 25, 8, 5, 0, 0, time.FixedZone("PDT", -7*60*60))
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "EWR",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departureTime},
+			{Departure: "ATL", Arrival: "EWR"},
+		},
+	}
+
+	body, err := MarshalFlightPathCSV(flightPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(body), "departure,arrival,departure_time,arrival_time\n"+
+		"SFO,ATL,2024-03-25T08:05:00-07:00,\n"+
+		"ATL,EWR,,\n")
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// CustomMethods serves the custom methods of a collection, such as "POST /flight_paths:diff", with the handler of
+// each method name. The route must end with a parameter named "method", such as "/flight_paths:method": gin 1.9 cannot
+// escape the colon, so the parameter captures it along with the method name.
+func CustomMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method, ok := strings.CutPrefix(c.Param("method"), ":")
+		if handler, found := handlers[method]; ok && found {
+			handler(c)
+			return
+		}
+		c.String(http.StatusNotFound, "404 page not found")
+	}
+}
+
+type DiffFlightPathsRequest struct {
+	Before CalculateFlightPathRequest `json:"before"`
+	After  CalculateFlightPathRequest `json:"after"`
+
+	// FlightLegFormat is how the flight legs of the response should be formatted. It defaults to arrays. Each flight
+	// path is validated on its own, so this is validated by validateFlightLegFormat instead of through a struct tag.
+	FlightLegFormat model.FlightLegFormat `json:"flight_leg_format"`
+
+	// before and after keep the raw flight paths, so that each one can be decoded and validated on its own
+	before json.RawMessage
+	after  json.RawMessage
+}
+
+// UnmarshalJSON only keeps the raw flight paths. They are decoded by validate, so that the errors of both are
+// reported together.
+func (r *DiffFlightPathsRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Before          json.RawMessage       `json:"before"`
+		After           json.RawMessage       `json:"after"`
+		FlightLegFormat model.FlightLegFormat `json:"flight_leg_format"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.before = raw.Before
+	r.after = raw.After
+	r.FlightLegFormat = raw.FlightLegFormat
+	return nil
+}
+
+// validate decodes and validates both flight paths. Their field errors are located under the "before" and "after"
+// fields of the request body.
+func (r *DiffFlightPathsRequest) validate() error {
+	if err := validateFlightLegFormat(r.FlightLegFormat); err != nil {
+		return err
+	}
+
+	var errs fieldErrors
+	for _, side := range []struct {
+		field   string
+		raw     json.RawMessage
+		request *CalculateFlightPathRequest
+	}{
+		{"before", r.before, &r.Before},
+		{"after", r.after, &r.After},
+	} {
+		var decodeErr error
+		if side.raw != nil {
+			decodeErr = json.Unmarshal(side.raw, side.request)
+		}
+
+		sideErrors, err := validateSubRequest(side.field, side.request, decodeErr)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, sideErrors...)
+	}
+
+	if len(errs) > 0 {
+		return NewValidationError(errs)
+	}
+	return nil
+}
+
+// DiffFlightPaths calculates the flight paths before and after a change, such as a reroute, and what changed from
+// one to the other.
+func DiffFlightPaths(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxRequestSize)
+
+	options, err := parseCalculationOptions(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+
+	var request DiffFlightPathsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+	if err := request.validate(); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	before, err := calculateFlightPath(c.Request.Context(), &request.Before, options)
+	if err != nil {
+		abortWithError(c, fmt.Errorf("before: %w", err))
+		return
+	}
+
+	after, err := calculateFlightPath(c.Request.Context(), &request.After, options)
+	if err != nil {
+		abortWithError(c, fmt.Errorf("after: %w", err))
+		return
+	}
+
+	language := negotiateLanguage(c.GetHeader("Accept-Language"))
+	describeFindings(before, language)
+	describeFindings(after, language)
+
+	diff := domain.DiffFlightPaths(before, after)
+	diff.FlightLegFormat = request.FlightLegFormat
+	c.JSON(200, diff)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffTestRouter(t *testing.T) *gin.Engine {
+	router := newTestRouter(t, DefaultConfig())
+	router.POST("/flight_paths/bulk", CalculateFlightPaths)
+	router.POST("/flight_paths:method", CustomMethods(map[string]gin.HandlerFunc{
+		"diff": DiffFlightPaths,
+	}))
+	return router
+}
+
+func postDiff(router *gin.Engine, target, payload string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(streamRecorder{recorder}, request)
+	return recorder
+}
+
+func TestDiffFlightPaths(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:diff", `{
+		"before": {"flight_legs": [["ATL", "GSO"], ["SFO", "ATL"], ["GSO", "EWR"]]},
+		"after": {"flight_legs": [["ORD", "EWR"], ["SFO", "ATL"], ["ATL", "ORD"]]},
+		"flight_leg_format": "string"
+	}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"before": {"origin": "SFO", "destination": "EWR", "flight_legs": ["SFO-ATL", "ATL-GSO", "GSO-EWR"]},
+		"after": {"origin": "SFO", "destination": "EWR", "flight_legs": ["SFO-ATL", "ATL-ORD", "ORD-EWR"]},
+		"origin_preserved": true,
+		"destination_preserved": true,
+		"added_flight_legs": ["ATL-ORD", "ORD-EWR"],
+		"removed_flight_legs": ["ATL-GSO", "GSO-EWR"],
+		"added_connections": ["ORD"],
+		"removed_connections": ["GSO"]
+	}`)
+}
+
+func TestDiffFlightPaths_OtherRoutesAreNotAffected(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	tests := []struct {
+		target   string
+		wantCode int
+	}{
+		{"/flight_paths", 200},
+		{"/flight_paths/bulk", 200},
+		{"/flight_paths:merge", 404},
+		{"/flight_pathsdiff", 404},
+		{"/flight_paths:diff/more", 404},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			response := postDiff(router, test.target, `{"flight_legs": [["SFO", "ATL"]]}`)
+			assert.Equal(t, response.Code, test.wantCode)
+		})
+	}
+}
+
+func TestDiffFlightPaths_FieldErrorsOfBothFlightPaths(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:diff", `{
+		"before": {"flight_legs": [["SFO", "AT1"], ["ATL"]]},
+		"after": {"flight_legs": [{"departure": "SFO"}]}
+	}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+
+	var paths []string
+	for _, param := range body.InvalidParams {
+		paths = append(paths, param.Path)
+	}
+	assert.Equal(t, paths, []string{"before.flight_legs[0][1]", "before.flight_legs[1]", "after.flight_legs[0].arrival"})
+}
+
+func TestDiffFlightPaths_MissingFlightPath(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:diff", `{"before": {"flight_legs": [["SFO", "ATL"]]}}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Code, CodeMissingFlightLegs)
+	assert.Equal(t, body.InvalidParams[0].Path, "after.flight_legs")
+}
+
+func TestDiffFlightPaths_InvalidRequest(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	tests := []struct {
+		payload  string
+		wantCode string
+	}{
+		{`{"before": `, CodeMalformedRequest},
+		{`{"before": 3, "after": {"flight_legs": [["SFO", "ATL"]]}}`, CodeMalformedRequest},
+		{`{"before": {"flight_legs": [["SFO", "ATL"]]}, "after": {"flight_legs": [["SFO", "ATL"]]},
+			"flight_leg_format": "xml"}`, CodeInvalidFlightLegFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.wantCode, func(t *testing.T) {
+			response := postDiff(router, "/flight_paths:diff", test.payload)
+
+			assert.Equal(t, response.Code, 400)
+			assert.Contains(t, response.Body.String(), `"code":"`+test.wantCode+`"`)
+		})
+	}
+}
+
+func TestDiffFlightPaths_DomainConflict(t *testing.T) {
+	router := newDiffTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:diff", `{
+		"before": {"flight_legs": [["SFO", "ATL"]]},
+		"after": {"flight_legs": [["SFO", "ATL"], ["ATL", "SFO"]]}
+	}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"flight_path_loop"`)
+}
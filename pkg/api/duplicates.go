@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+)
+
+var ErrInvalidDuplicatePolicy = errors.New("invalid duplicate policy - must be either reject, skip or warn")
+
+var duplicatePolicies = map[string]domain.DuplicatePolicy{
+	"reject": domain.RejectDuplicates,
+	"skip":   domain.SkipDuplicates,
+	"warn":   domain.WarnDuplicates,
+}
+
+// parseDuplicatePolicy tells what to do with duplicate flight legs, as told by the duplicates query parameter. They
+// are rejected by default.
+func parseDuplicatePolicy(c *gin.Context) (domain.DuplicatePolicy, error) {
+	value, ok := c.GetQuery("duplicates")
+	if !ok {
+		return domain.RejectDuplicates, nil
+	}
+
+	policy, ok := duplicatePolicies[value]
+	if !ok {
+		return domain.RejectDuplicates, ErrInvalidDuplicatePolicy
+	}
+	return policy, nil
+}
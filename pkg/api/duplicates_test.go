@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func postDuplicates(router *gin.Engine, query, payload, acceptLanguage string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths?"+query, strings.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", acceptLanguage)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+const duplicatesPayload = `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"], ["ATL", "EWR"]], "flight_leg_format": "string"}`
+
+func TestCalculateFlightPath_DuplicatesAreRejectedByDefault(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	for _, query := range []string{"", "duplicates=reject"} {
+		t.Run(query, func(t *testing.T) {
+			response := postDuplicates(router, query, duplicatesPayload, "")
+
+			assert.Equal(t, response.Code, 400)
+			assert.Contains(t, response.Body.String(), `"code":"duplicate_flight_leg"`)
+		})
+	}
+}
+
+func TestCalculateFlightPath_SkipDuplicates(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postDuplicates(router, "duplicates=skip", duplicatesPayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{"origin":"SFO","destination":"EWR","flight_legs":["SFO-ATL","ATL-EWR"]}`)
+}
+
+func TestCalculateFlightPath_WarnDuplicates(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"in memory", DefaultConfig()},
+		{"streamed", streamingConfig()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, test.config)
+
+			response := postDuplicates(router, "duplicates=warn", duplicatesPayload, "pt-BR")
+
+			assert.Equal(t, response.Code, 200)
+			assert.JSONEq(t, response.Body.String(), `{
+				"origin": "SFO",
+				"destination": "EWR",
+				"flight_legs": ["SFO-ATL", "ATL-EWR"],
+				"warnings": [{
+					"code": "duplicate_flight_leg",
+					"message": "O mesmo trecho de voo foi informado mais de uma vez.",
+					"flight_leg": "ATL-EWR"
+				}]
+			}`)
+		})
+	}
+}
+
+func TestCalculateFlightPath_SameAirportsAtDifferentTimesAreNotDuplicates(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postDuplicates(router, "duplicates=skip", `{"flight_legs": [
+		{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:00:00-07:00"},
+		{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-26T08:00:00-07:00"}
+	]}`, "")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"multiple_outbound_legs"`)
+}
+
+func TestCalculateFlightPath_InvalidDuplicatePolicy(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postDuplicates(router, "duplicates=ignore", duplicatesPayload, "")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_duplicate_policy"`)
+}
+
+func TestCalculateFlightPaths_WarnDuplicates(t *testing.T) {
+	router := newBulkTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsBulk(router, duplicatesPayload+"\n", "?duplicates=warn")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(),
+		`"warnings":[{"code":"duplicate_flight_leg","message":"The same flight leg was given more than once.",`+
+			`"flight_leg":"ATL-EWR"}]`)
+}
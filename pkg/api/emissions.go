@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	ErrInvalidEmissionsOptions = errors.New(
+		"invalid emissions options - emissions must be a boolean, and cabin_class a cabin class given with emissions")
+	ErrAirportsUnavailable = errors.New("airports unavailable - AIRPORTS_FILE is needed")
+)
+
+// emissionsOptions tell whether the emissions of the flight path should be estimated, as told by the emissions and
+// cabin_class query parameters.
+type emissionsOptions struct {
+	enabled    bool
+	cabinClass domain.CabinClass
+}
+
+func parseEmissionsOptions(c *gin.Context) (emissionsOptions, error) {
+	options := emissionsOptions{cabinClass: domain.CabinClassEconomy}
+
+	if value, ok := c.GetQuery("emissions"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, ErrInvalidEmissionsOptions
+		}
+		options.enabled = enabled
+	}
+
+	if value, ok := c.GetQuery("cabin_class"); ok {
+		cabinClass := domain.CabinClass(value)
+		if _, found := emissionsMethod.CabinClasses[cabinClass]; !found || !options.enabled {
+			return options, ErrInvalidEmissionsOptions
+		}
+		options.cabinClass = cabinClass
+	}
+
+	return options, nil
+}
+
+func (o emissionsOptions) apply(flightPath *model.FlightPath) error {
+	if !o.enabled {
+		return nil
+	}
+	if airports == nil {
+		return ErrAirportsUnavailable
+	}
+	return domain.EstimateEmissions(flightPath, airports, emissionsMethod, o.cabinClass)
+}
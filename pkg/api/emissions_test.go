@@ -0,0 +1,121 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const emissionsPayload = `{"flight_legs": [["ATL", "GSO"], ["SFO", "ATL"]], "flight_leg_format": "object"}`
+
+func TestCalculateFlightPath_Emissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"in memory", routeNetworkConfig()},
+		{"streamed", func() Config {
+			c := routeNetworkConfig()
+			c.StreamingThreshold = 1
+			return c
+		}()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, test.config)
+
+			response := postDuplicates(router, "emissions=true&cabin_class=business", emissionsPayload, "")
+
+			assert.Equal(t, response.Code, 200)
+			assert.JSONEq(t, response.Body.String(), `{
+				"origin": "SFO",
+				"destination": "GSO",
+				"flight_legs": [
+					{"departure": "SFO", "arrival": "ATL", "emissions": {"distance_km": 3709, "co2_kg": 1613.6}},
+					{"departure": "ATL", "arrival": "GSO", "emissions": {"distance_km": 532, "co2_kg": 277.7}}
+				],
+				"emissions": {"distance_km": 4241, "co2_kg": 1891.3}
+			}`)
+		})
+	}
+}
+
+func TestCalculateFlightPath_EmissionsInArrayFormat(t *testing.T) {
+	router := newTestRouter(t, routeNetworkConfig())
+
+	response := postDuplicates(router, "emissions=true", `{"flight_legs": [["atl", "gso"], ["sfo", "atl"]]}`, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "sfo",
+		"destination": "gso",
+		"flight_legs": [["sfo", "atl"], ["atl", "gso"]],
+		"emissions": {"distance_km": 4241, "co2_kg": 652.2},
+		"flight_leg_emissions": [
+			{"distance_km": 3709, "co2_kg": 556.4},
+			{"distance_km": 532, "co2_kg": 95.8}
+		]
+	}`)
+}
+
+func TestCalculateFlightPath_EmissionsOfReturnTrip(t *testing.T) {
+	router := newTestRouter(t, routeNetworkConfig())
+
+	response := postDuplicates(router, "emissions=true&return_trip=true", emissionsPayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(), `"emissions":{"distance_km":4241,"co2_kg":652.2}`)
+}
+
+func TestCalculateFlightPath_EmissionsMethodFile(t *testing.T) {
+	c := routeNetworkConfig()
+	c.EmissionsMethodFile = "../../data/emissions_method.json"
+	router := newTestRouter(t, c)
+
+	response := postDuplicates(router, "emissions=true", emissionsPayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(), `"emissions":{"distance_km":4241,"co2_kg":652.2}`)
+}
+
+func TestCalculateFlightPath_EmissionsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		query      string
+		payload    string
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid emissions", routeNetworkConfig(), "emissions=maybe", emissionsPayload, 400, CodeInvalidEmissionsOptions},
+		{"unknown cabin class", routeNetworkConfig(), "emissions=true&cabin_class=coach", emissionsPayload, 400,
+			CodeInvalidEmissionsOptions},
+		{"cabin class without emissions", routeNetworkConfig(), "cabin_class=first", emissionsPayload, 400,
+			CodeInvalidEmissionsOptions},
+		{"without airports", DefaultConfig(), "emissions=true", emissionsPayload, 501, CodeAirportsUnavailable},
+		{"unknown airport", routeNetworkConfig(), "emissions=true", `{"flight_legs": [["SFO", "XYZ"]]}`, 400,
+			CodeMissingAirportCoordinates},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, test.config)
+
+			response := postDuplicates(router, test.query, test.payload, "")
+
+			assert.Equal(t, response.Code, test.wantStatus)
+			assert.Contains(t, response.Body.String(), `"code":"`+test.wantCode+`"`)
+		})
+	}
+}
+
+func TestInit_InvalidEmissionsMethodFile(t *testing.T) {
+	c := DefaultConfig()
+	c.EmissionsMethodFile = "../../data/routes.dat"
+	t.Cleanup(func() {
+		config = DefaultConfig()
+	})
+
+	assert.ErrorContains(t, Init(c), "../../data/routes.dat: invalid emissions method")
+}
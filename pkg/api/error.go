@@ -1,21 +1,170 @@
 package api
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrStorageUnavailable is meant to be wrapped by persistence and cache layers whenever the backing store cannot be
+// reached, so that the API can report a transient failure instead of an internal error.
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
+// ErrorClass groups errors that should be reported to the client the same way.
+type ErrorClass int
+
+const (
+	ErrorClassInternal ErrorClass = iota
+	ErrorClassValidation
+	ErrorClassDomainConflict
+	ErrorClassTimeout
+	ErrorClassStorageUnavailable
+	ErrorClassRequestTooLarge
+	ErrorClassNotFound
+	ErrorClassNotImplemented
+	ErrorClassCanceled
+)
+
+type errorClassProperties struct {
+	status     int
+	code       string
+	title      string
+	retryable  bool
+	retryAfter time.Duration
+}
+
+var errorClasses = map[ErrorClass]errorClassProperties{
+	ErrorClassInternal: {
+		status: 500,
+		code:   CodeInternalError,
+		title:  "Internal server error",
+	},
+	ErrorClassValidation: {
+		status: 400,
+		code:   CodeValidationError,
+		title:  "Invalid request",
+	},
+	ErrorClassDomainConflict: {
+		status: 400,
+		code:   CodeDomainConflict,
+		title:  "Invalid flight path",
+	},
+	ErrorClassTimeout: {
+		status:     504,
+		code:       CodeTimeout,
+		title:      "Request timed out",
+		retryable:  true,
+		retryAfter: time.Second,
+	},
+	// ErrorClassCanceled is for requests the client gave up on before they were served, unlike timeouts, which the
+	// server gave up on.
+	ErrorClassCanceled: {
+		status:     503,
+		code:       CodeRequestCanceled,
+		title:      "Request canceled",
+		retryable:  true,
+		retryAfter: time.Second,
+	},
+	ErrorClassStorageUnavailable: {
+		status:     503,
+		code:       CodeStorageUnavailable,
+		title:      "Storage unavailable",
+		retryable:  true,
+		retryAfter: 5 * time.Second,
+	},
+	ErrorClassRequestTooLarge: {
+		status: 413,
+		code:   CodeRequestTooLarge,
+		title:  "Request too large",
+	},
+	ErrorClassNotFound: {
+		status: 404,
+		code:   CodeNotFound,
+		title:  "Not found",
+	},
+	// ErrorClassNotImplemented is for features that need a dataset the server was not configured with. They are not
+	// retryable, since they won't work until the server is reconfigured.
+	ErrorClassNotImplemented: {
+		status: 501,
+		code:   CodeNotImplemented,
+		title:  "Not implemented",
+	},
+}
+
+// Status is the HTTP response status code used for this class of errors.
+func (class ErrorClass) Status() int {
+	return errorClasses[class].status
+}
+
+// Code is a stable, machine-readable identifier for this class of errors. It is used when no specific code from the
+// error catalogue applies.
+func (class ErrorClass) Code() string {
+	return errorClasses[class].code
+}
+
+// Title is a short, human-readable summary of this class of errors.
+func (class ErrorClass) Title() string {
+	return errorClasses[class].title
+}
+
+// Retryable tells if retrying the very same request at a later time might succeed.
+func (class ErrorClass) Retryable() bool {
+	return errorClasses[class].retryable
+}
+
+// RetryAfter is how long the client is advised to wait before retrying. It is zero if the class is not retryable.
+func (class ErrorClass) RetryAfter() time.Duration {
+	return errorClasses[class].retryAfter
+}
+
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// NewValidationError marks an error that was caused by a malformed or invalid request.
+func NewValidationError(err error) error {
+	return &classifiedError{class: ErrorClassValidation, err: err}
+}
+
+// ClassifyError determines the ErrorClass of any error returned while serving a request. Errors that cannot be
+// recognized are considered internal.
+func ClassifyError(err error) ErrorClass {
+	return errorCatalogue[ErrorCode(err)].class
+}
+
 type ErrorResponse struct {
-	Error     bool   `json:"error"`
-	Retryable bool   `json:"retryable"`
-	Message   string `json:"message"`
-}
-
-func NewErrorResponse(err error) *ErrorResponse {
-	//
-	// TODO: future improvements, if we were to run this in production:
-	//  - Determine retryable (transient) errors - TCP timeouts, connection errors or SQL transaction isolation faults
-	//  - Be careful to not expose sensitive information in the error message. Ideally we should only allow certain
-	//    error messages to be visible to the public
-	//
+	Error               bool                 `json:"error"`
+	Retryable           bool                 `json:"retryable"`
+	Code                string               `json:"code"`
+	Message             string               `json:"message"`
+	CorrelationID       string               `json:"correlation_id,omitempty"`
+	InvalidParams       []FieldError         `json:"invalid_params,omitempty"`
+	Conflicts           []FlightLegConflict  `json:"conflicts,omitempty"`
+	SuggestedFlightLegs []SuggestedFlightLeg `json:"suggested_flight_legs,omitempty"`
+}
+
+// NewErrorResponse only exposes the public message registered in the error catalogue, in the given language. The
+// error itself must be logged along with the correlation ID, so that the response can be traced back to the details.
+func NewErrorResponse(err error, correlationID, language string) *ErrorResponse {
+	code := ErrorCode(err)
+	entry := errorCatalogue[code]
+
 	return &ErrorResponse{
-		Error:     true,
-		Retryable: false,
-		Message:   err.Error(),
+		Error:               true,
+		Retryable:           entry.class.Retryable(),
+		Code:                code,
+		Message:             PublicMessage(code, language),
+		CorrelationID:       correlationID,
+		InvalidParams:       newFieldErrorResponses(err, language),
+		Conflicts:           newFlightLegConflictResponses(err),
+		SuggestedFlightLegs: newSuggestedFlightLegResponses(err),
 	}
 }
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantClass ErrorClass
+	}{
+		{
+			name:      "validation error",
+			err:       NewValidationError(errors.New("bad input")),
+			wantClass: ErrorClassValidation,
+		},
+		{
+			name:      "empty flight path",
+			err:       domain.ErrEmptyFlightPath,
+			wantClass: ErrorClassValidation,
+		},
+		{
+			name:      "invalid flight path",
+			err:       fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrOutboundConnectionExists),
+			wantClass: ErrorClassDomainConflict,
+		},
+		{
+			name:      "disconnected flight path",
+			err:       fmt.Errorf("%w; there's no flight leg leaving airport SFO", domain.ErrDisconnectedFlightPath),
+			wantClass: ErrorClassDomainConflict,
+		},
+		{
+			name:      "deadline exceeded",
+			err:       fmt.Errorf("flight path calculation aborted; %w", context.DeadlineExceeded),
+			wantClass: ErrorClassTimeout,
+		},
+		{
+			name:      "canceled",
+			err:       fmt.Errorf("flight path calculation aborted; %w", context.Canceled),
+			wantClass: ErrorClassCanceled,
+		},
+		{
+			name:      "storage unavailable",
+			err:       fmt.Errorf("unable to save flight path: %w", ErrStorageUnavailable),
+			wantClass: ErrorClassStorageUnavailable,
+		},
+		{
+			name:      "request too large",
+			err:       NewValidationError(&http.MaxBytesError{Limit: 1024}),
+			wantClass: ErrorClassRequestTooLarge,
+		},
+		{
+			name:      "too many flight legs",
+			err:       NewValidationError(fmt.Errorf("%w - the maximum is 10", ErrTooManyFlightLegs)),
+			wantClass: ErrorClassRequestTooLarge,
+		},
+		{
+			name:      "route not found",
+			err:       fmt.Errorf("%w from GSO to SFO with up to 2 stops", domain.ErrRouteNotFound),
+			wantClass: ErrorClassNotFound,
+		},
+		{
+			name:      "unknown error",
+			err:       errors.New("something unexpected"),
+			wantClass: ErrorClassInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, ClassifyError(tt.err), tt.wantClass)
+		})
+	}
+}
+
+func TestErrorClass_Properties(t *testing.T) {
+	tests := []struct {
+		class          ErrorClass
+		wantStatus     int
+		wantCode       string
+		wantTitle      string
+		wantRetryable  bool
+		wantRetryAfter time.Duration
+	}{
+		{ErrorClassInternal, 500, "internal_error", "Internal server error", false, 0},
+		{ErrorClassValidation, 400, "validation_error", "Invalid request", false, 0},
+		{ErrorClassDomainConflict, 400, "domain_conflict", "Invalid flight path", false, 0},
+		{ErrorClassTimeout, 504, "timeout", "Request timed out", true, time.Second},
+		{ErrorClassCanceled, 503, "request_canceled", "Request canceled", true, time.Second},
+		{ErrorClassStorageUnavailable, 503, "storage_unavailable", "Storage unavailable", true, 5 * time.Second},
+		{ErrorClassRequestTooLarge, 413, "request_too_large", "Request too large", false, 0},
+		{ErrorClassNotFound, 404, "not_found", "Not found", false, 0},
+		{ErrorClassNotImplemented, 501, "not_implemented", "Not implemented", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantCode, func(t *testing.T) {
+			assert.Equal(t, tt.class.Status(), tt.wantStatus)
+			assert.Equal(t, tt.class.Code(), tt.wantCode)
+			assert.Equal(t, tt.class.Title(), tt.wantTitle)
+			assert.Equal(t, tt.class.Retryable(), tt.wantRetryable)
+			assert.Equal(t, tt.class.RetryAfter(), tt.wantRetryAfter)
+		})
+	}
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	response := NewErrorResponse(fmt.Errorf("flight path calculation aborted; %w", context.DeadlineExceeded), "abc", "en")
+
+	assert.Equal(t, response, &ErrorResponse{
+		Error:         true,
+		Retryable:     true,
+		Code:          "timeout",
+		Message:       "The request took too long to be processed. Please try again later.",
+		CorrelationID: "abc",
+	})
+}
+
+func TestNewErrorResponse_DoesNotExposeInternalDetails(t *testing.T) {
+	response := NewErrorResponse(errors.New("pq: connection refused at 10.0.0.7:5432"), "abc", "en")
+
+	assert.Equal(t, response.Code, "internal_error")
+	assert.Equal(t, response.Message, "An unexpected error occurred while processing the request.")
+}
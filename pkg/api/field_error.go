@@ -0,0 +1,197 @@
+package api
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	goValidator "github.com/go-playground/validator/v10"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+	"github.com/felipead/flight-path-tracker/pkg/validator"
+)
+
+// FieldLocation locates a value in the request body. Values of JSON bodies are located by their path, such as
+// "flight_legs[36][0]", while values of CSV bodies are located by their row number and column name.
+type FieldLocation struct {
+	Path   string `json:"path,omitempty"`
+	Row    int    `json:"row,omitempty"`
+	Column string `json:"column,omitempty"`
+}
+
+// fieldError is an error caused by a single value of the request body.
+type fieldError struct {
+	location FieldLocation
+	// leg is the index of the flight leg that holds the value, or -1 if the value is not part of a flight leg
+	leg   int
+	code  string
+	value interface{}
+	err   error
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// fieldErrors aggregates the errors of every offending value in the request body, so that all of them can be
+// reported at once.
+type fieldErrors []*fieldError
+
+func (e fieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e fieldErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// newFlightLegFieldError converts an error found while decoding the flight leg at the given index of a JSON body.
+func newFlightLegFieldError(index int, err error) *fieldError {
+	path := fmt.Sprintf("flight_legs[%v]", index)
+
+	var legError *model.FlightLegError
+	if !errors.As(err, &legError) {
+		return &fieldError{location: FieldLocation{Path: path}, leg: index, code: CodeInvalidFlightLeg, err: err}
+	}
+
+	switch {
+	case legError.Field != "":
+		path = fmt.Sprintf("%v.%v", path, legError.Field)
+	case legError.Position >= 0:
+		path = fmt.Sprintf("%v[%v]", path, legError.Position)
+	}
+
+	return &fieldError{
+		location: FieldLocation{Path: path},
+		leg:      index,
+		code:     flightLegErrorCode(err),
+		value:    legError.Value,
+		err:      err,
+	}
+}
+
+func flightLegErrorCode(err error) string {
+	switch {
+	case errors.Is(err, model.ErrUnknownFlightLegField):
+		return CodeUnknownFlightLegField
+	case errors.Is(err, model.ErrInvalidFlightLegTime):
+		return CodeInvalidFlightLegTime
+	default:
+		return CodeInvalidFlightLeg
+	}
+}
+
+// newValidationFieldErrors converts validation errors. The locate function finds the location of each value in the
+// request body, and the flight leg it belongs to, from the namespace of its struct field.
+func newValidationFieldErrors(
+	validationErrors goValidator.ValidationErrors, locate func(namespace string) (FieldLocation, int),
+) fieldErrors {
+	errs := make(fieldErrors, 0, len(validationErrors))
+	for _, err := range validationErrors {
+		location, leg := locate(err.StructNamespace())
+		errs = append(errs, &fieldError{
+			location: location,
+			leg:      leg,
+			code:     fieldErrorCode(err),
+			value:    err.Value(),
+			err:      err,
+		})
+	}
+	return errs
+}
+
+// mergeFieldErrors combines the errors found while decoding the request body with the ones found while validating
+// it, in the order of the flight legs. Flight legs that could not be decoded are left empty, so their validation errors
+// would only be noise. So would all validation errors if the flight legs could not be decoded at all, such as when the
+// header of a CSV body is invalid.
+func mergeFieldErrors(decodeErrors, validationErrors fieldErrors) fieldErrors {
+	merged := append(fieldErrors{}, decodeErrors...)
+
+	failedLegs := make(map[int]bool, len(decodeErrors))
+	failedAll := false
+	for _, err := range decodeErrors {
+		if err.leg >= 0 {
+			failedLegs[err.leg] = true
+		} else {
+			failedAll = true
+		}
+	}
+
+	for _, err := range validationErrors {
+		if !failedAll && !failedLegs[err.leg] {
+			merged = append(merged, err)
+		}
+	}
+
+	sortFieldErrors(merged)
+	return merged
+}
+
+// sortFieldErrors sorts the errors by the flight leg they belong to, with the errors that are not part of a flight leg
+// first, and then by their CSV row and column. Otherwise, the errors of the same flight leg keep their order, which is
+// the order of its fields.
+func sortFieldErrors(errs fieldErrors) {
+	slices.SortStableFunc(errs, func(a, b *fieldError) int {
+		return cmp.Or(
+			cmp.Compare(a.leg, b.leg),
+			cmp.Compare(a.location.Row, b.location.Row),
+			cmp.Compare(csvColumnRank(a.location.Column), csvColumnRank(b.location.Column)),
+		)
+	})
+}
+
+// FieldError is the public representation of an error caused by a single value of the request body.
+type FieldError struct {
+	FieldLocation
+	Code    string      `json:"code"`
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message"`
+}
+
+func newFieldErrorResponses(err error, language string) []FieldError {
+	var errs fieldErrors
+	var validationErrors goValidator.ValidationErrors
+
+	switch {
+	case errors.As(err, &errs):
+	case errors.As(err, &validationErrors):
+		errs = newValidationFieldErrors(validationErrors, new(CalculateFlightPathRequest).locate)
+	default:
+		return nil
+	}
+
+	responses := make([]FieldError, 0, len(errs))
+	for _, e := range errs {
+		responses = append(responses, FieldError{
+			FieldLocation: e.location,
+			Code:          e.code,
+			Value:         e.value,
+			Message:       e.publicMessage(language),
+		})
+	}
+	return responses
+}
+
+// publicMessage translates validation errors through the go-playground translators, which describe the failed
+// constraint of the field by its JSON name. Other errors get the message of their code from the error catalogue.
+func (e *fieldError) publicMessage(language string) string {
+	var validationError goValidator.FieldError
+	if translator := validator.GetTranslator(language); translator != nil && errors.As(e.err, &validationError) {
+		return validationError.Translate(translator)
+	}
+	return PublicMessage(e.code, language)
+}
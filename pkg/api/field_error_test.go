@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFieldErrors(t *testing.T) {
+	decodeErrors := fieldErrors{
+		{location: FieldLocation{Path: "flight_legs[1]"}, leg: 1, code: CodeInvalidFlightLeg, err: errors.New("a")},
+		{location: FieldLocation{Path: "flight_legs[3][0]"}, leg: 3, code: CodeInvalidFlightLeg, err: errors.New("b")},
+	}
+	validationErrors := fieldErrors{
+		{location: FieldLocation{Path: "flight_legs[1][0]"}, leg: 1, code: CodeMissingAirportCode, err: errors.New("c")},
+		{location: FieldLocation{Path: "flight_legs[1][1]"}, leg: 1, code: CodeMissingAirportCode, err: errors.New("d")},
+		{location: FieldLocation{Path: "flight_legs[3][1]"}, leg: 3, code: CodeMissingAirportCode, err: errors.New("e")},
+		{location: FieldLocation{Path: "flight_legs[10][0]"}, leg: 10, code: CodeInvalidAirportCode, err: errors.New("f")},
+	}
+
+	merged := mergeFieldErrors(decodeErrors, validationErrors)
+
+	assert.Equal(t, merged, fieldErrors{decodeErrors[0], decodeErrors[1], validationErrors[3]})
+}
+
+func TestMergeFieldErrors_CSVHeaderErrors(t *testing.T) {
+	decodeErrors := fieldErrors{
+		{location: FieldLocation{Row: 1, Column: "gate"}, leg: -1, code: CodeInvalidCSVHeader, err: errors.New("a")},
+		{location: FieldLocation{Row: 1, Column: "arrival"}, leg: -1, code: CodeInvalidCSVHeader, err: errors.New("b")},
+		{location: FieldLocation{Row: 1, Column: "departure"}, leg: -1, code: CodeInvalidCSVHeader, err: errors.New("c")},
+	}
+	validationErrors := fieldErrors{
+		{location: FieldLocation{}, leg: -1, code: CodeMissingFlightLegs, err: errors.New("d")},
+	}
+
+	merged := mergeFieldErrors(decodeErrors, validationErrors)
+
+	assert.Equal(t, merged, fieldErrors{decodeErrors[2], decodeErrors[1], decodeErrors[0]})
+}
+
+func TestMergeFieldErrors_NoErrors(t *testing.T) {
+	assert.Empty(t, mergeFieldErrors(nil, nil))
+}
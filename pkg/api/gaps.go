@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// SuggestedFlightLeg is the public representation of a flight leg that was probably left out of a disconnected flight
+// path. Plausibility goes from 0, exclusive, to 1.
+type SuggestedFlightLeg struct {
+	Departure    model.AirportCode `json:"departure"`
+	Arrival      model.AirportCode `json:"arrival"`
+	Plausibility float64           `json:"plausibility"`
+}
+
+func newSuggestedFlightLegResponses(err error) []SuggestedFlightLeg {
+	var disconnected *domain.DisconnectedFlightPathError
+	if !errors.As(err, &disconnected) {
+		return nil
+	}
+
+	responses := make([]SuggestedFlightLeg, 0, len(disconnected.SuggestedFlightLegs))
+	for _, leg := range disconnected.SuggestedFlightLegs {
+		responses = append(responses, SuggestedFlightLeg{
+			Departure:    leg.Departure,
+			Arrival:      leg.Arrival,
+			Plausibility: leg.Plausibility,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateFlightPath_SuggestedFlightLegs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"in memory", DefaultConfig()},
+		{"streamed", streamingConfig()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newTestRouter(t, test.config)
+
+			response := postFlightPaths(router, `{"flight_legs": [["SFO", "ATL"], ["GSO", "IND"]]}`)
+
+			assert.Equal(t, response.Code, 400)
+
+			var body ErrorResponse
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+			assert.Equal(t, body.Code, CodeDisconnectedFlightPath)
+			assert.Equal(t, body.SuggestedFlightLegs, []SuggestedFlightLeg{
+				{Departure: "ATL", Arrival: "GSO", Plausibility: 1},
+				{Departure: "IND", Arrival: "SFO", Plausibility: 1},
+			})
+		})
+	}
+}
+
+func TestCalculateFlightPath_NoSuggestedFlightLegsForOtherErrors(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": [["ATL", "EWR"], ["EWR", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.NotContains(t, response.Body.String(), "suggested_flight_legs")
+}
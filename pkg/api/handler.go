@@ -1,38 +1,222 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	goValidator "github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 
 	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
 	"github.com/felipead/flight-path-tracker/pkg/validator"
 )
 
 var log = logrus.New()
 
 func CalculateFlightPath(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxRequestSize)
+
+	returnTrip, err := parseReturnTripOptions(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+
+	emissions, err := parseEmissionsOptions(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+
+	options, err := parseCalculationOptions(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+
+	if shouldStream(c) {
+		calculateStreamedFlightPath(c, returnTrip, emissions, options)
+		return
+	}
+
 	var request CalculateFlightPathRequest
+	if err := validateRequest(&request, bindRequest(c, &request)); err != nil {
+		abortWithError(c, err)
+		return
+	}
 
-	if err := c.BindJSON(&request); err != nil {
-		c.AbortWithStatusJSON(400, NewErrorResponse(err))
+	flightPath, err := calculateFlightPath(c.Request.Context(), &request, options)
+	if err == nil {
+		flightPath, err = returnTrip.apply(flightPath)
+	}
+	if err == nil {
+		err = emissions.apply(flightPath)
+	}
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
-	validate := validator.GetValidator()
-	if err := validate.Struct(&request); err != nil {
-		c.AbortWithStatusJSON(400, NewErrorResponse(err))
+	renderFlightPath(c, flightPath)
+}
+
+// shouldStream tells if the request body is JSON that is too large, or of unknown size, to be read into memory at once
+func shouldStream(c *gin.Context) bool {
+	contentType := c.ContentType()
+	if contentType != gin.MIMEJSON && contentType != "" {
+		return false
+	}
+	return c.Request.ContentLength < 0 || c.Request.ContentLength > config.StreamingThreshold
+}
+
+func calculateStreamedFlightPath(
+	c *gin.Context, returnTrip returnTripOptions, emissions emissionsOptions, options domain.CalculationOptions,
+) {
+	stream := newFlightPathStream(c.Request.Body, options)
+	defer stream.builder.Release()
+
+	if err := stream.decode(c.Request.Context()); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"FlightLegCount": stream.builder.Length(),
+	}).Info("Calculating streamed flight path")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.CalculationTimeout)
+	defer cancel()
+
+	flightPath, err := stream.builder.Build(ctx)
+	if err == nil {
+		flightPath.FlightLegFormat = stream.flightLegFormat
+		flightPath, err = returnTrip.apply(flightPath)
+	}
+	if err == nil {
+		err = emissions.apply(flightPath)
+	}
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	renderFlightPath(c, flightPath)
+}
+
+// renderFlightPath writes the flight path as JSON or CSV, as told by the Accept header, along with its anomalies.
+// Warnings and anomalies are only part of JSON responses.
+func renderFlightPath(c *gin.Context, flightPath *model.FlightPath) {
+	language := negotiateLanguage(c.GetHeader("Accept-Language"))
+	detectAnomalies(flightPath)
+	describeFindings(flightPath, language)
+
+	c.Header("Vary", "Accept")
+	switch c.NegotiateFormat(gin.MIMEJSON, MIMECSV) {
+	case MIMECSV:
+		body, err := MarshalFlightPathCSV(flightPath)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Data(200, MIMECSV+"; charset=utf-8", body)
+	default:
+		c.JSON(200, flightPath)
+	}
+}
+
+// validateRequest checks a request that was decoded with the given error. As long as the decoding error is made of
+// field errors, the flight legs that could be decoded are validated as well, and all errors are returned together.
+func validateRequest(request *CalculateFlightPathRequest, decodeErr error) error {
+	var decodeErrors fieldErrors
+	if decodeErr != nil && !errors.As(decodeErr, &decodeErrors) {
+		return NewValidationError(decodeErr)
+	}
+
+	if len(request.FlightLegs) > config.MaxFlightLegs {
+		return NewValidationError(tooManyFlightLegsError())
+	}
+
+	var validationErrors fieldErrors
+	validate := validator.GetValidator()
+	if err := validate.Struct(request); err != nil {
+		var errs goValidator.ValidationErrors
+		if !errors.As(err, &errs) {
+			return err
+		}
+		validationErrors = newValidationFieldErrors(errs, request.locate)
+	}
+
+	if errs := mergeFieldErrors(decodeErrors, validationErrors); len(errs) > 0 {
+		return NewValidationError(errs)
+	}
+	return nil
+}
+
+func calculateFlightPath(
+	ctx context.Context, request *CalculateFlightPathRequest, options domain.CalculationOptions,
+) (*model.FlightPath, error) {
 	log.WithFields(logrus.Fields{
 		"FlightLegs": request.FlightLegs,
 	}).Info("Calculating flight path")
 
-	flightPath, err := domain.CalculateFlightPath(request.FlightLegs)
+	ctx, cancel := context.WithTimeout(ctx, config.CalculationTimeout)
+	defer cancel()
+
+	flightPath, err := domain.CalculateFlightPathWithOptions(ctx, request.FlightLegs, options)
 	if err != nil {
-		c.AbortWithStatusJSON(400, NewErrorResponse(err))
-		return
+		return nil, err
 	}
 
-	c.JSON(200, flightPath)
+	flightPath.FlightLegFormat = request.FlightLegFormat
+	return flightPath, nil
+}
+
+// bindRequest decodes the request body according to its content type, which is JSON unless told otherwise
+func bindRequest(c *gin.Context, request *CalculateFlightPathRequest) error {
+	if c.ContentType() == MIMECSV {
+		return request.UnmarshalCSV(c.Request.Body)
+	}
+	return c.ShouldBindJSON(request)
+}
+
+func abortWithError(c *gin.Context, err error) {
+	class := ClassifyError(err)
+	correlationID := getCorrelationID(c)
+
+	logError(log.WithField("CorrelationID", correlationID), err)
+
+	if retryAfter := class.RetryAfter(); retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	}
+
+	// Clients must opt in for RFC 7807 problem documents, so that the legacy error format is kept for backward
+	// compatibility.
+	language := negotiateLanguage(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", language)
+	c.Header("Vary", "Accept, Accept-Language")
+
+	switch c.NegotiateFormat(gin.MIMEJSON, MIMEProblemJSON) {
+	case MIMEProblemJSON:
+		c.Header("Content-Type", MIMEProblemJSON)
+		c.AbortWithStatusJSON(class.Status(), NewProblem(err, correlationID, c.Request.URL.RequestURI(), language))
+	default:
+		c.AbortWithStatusJSON(class.Status(), NewErrorResponse(err, correlationID, language))
+	}
+}
+
+// logError logs internal errors as such, while errors caused by the client are only worth an info entry
+func logError(entry *logrus.Entry, err error) {
+	entry = entry.WithFields(logrus.Fields{
+		"Code":  ErrorCode(err),
+		"Error": err,
+	})
+	if ClassifyError(err) == ErrorClassInternal {
+		entry.Error("Unable to serve request")
+	} else {
+		entry.Info("Rejected request")
+	}
 }
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func newTestRouter(t testing.TB, c Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	assert.NoError(t, Init(c))
+	t.Cleanup(func() {
+		config = DefaultConfig()
+		routes = nil
+		airports = nil
+		emissionsMethod = domain.DefaultEmissionsMethod
+	})
+
+	router := gin.New()
+	router.Use(CorrelationID())
+	router.POST("/flight_paths", CalculateFlightPath)
+	return router
+}
+
+func postFlightPaths(router *gin.Engine, payload string) *httptest.ResponseRecorder {
+	return postFlightPathsAccepting(router, payload, "")
+}
+
+func postFlightPathsAccepting(router *gin.Engine, payload, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths", strings.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCalculateFlightPath_Success(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(),
+		`{"origin":"SFO","destination":"EWR","flight_legs":[["SFO","ATL"],["ATL","EWR"]]}`)
+}
+
+func TestCalculateFlightPath_ValidationError(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": [["ATL", "E5R"]]}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Empty(t, response.Header().Get("Retry-After"))
+	assert.Contains(t, response.Body.String(), `"code":"invalid_airport_code"`)
+	assert.NotContains(t, response.Body.String(), "Key: 'CalculateFlightPathRequest")
+	assert.Contains(t, response.Body.String(), `"retryable":false`)
+}
+
+func TestCalculateFlightPath_DomainConflict(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": [["ATL", "EWR"], ["EWR", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"flight_path_loop"`)
+}
+
+func TestCalculateFlightPath_Timeout(t *testing.T) {
+	c := DefaultConfig()
+	c.CalculationTimeout = -time.Second // already expired
+	router := newTestRouter(t, c)
+
+	response := postFlightPaths(router, `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 504)
+	assert.Equal(t, response.Header().Get("Retry-After"), "1")
+	assert.Contains(t, response.Body.String(), `"code":"timeout"`)
+	assert.Contains(t, response.Body.String(), `"retryable":true`)
+}
+
+func TestCalculateFlightPath_ErrorIsLinkedToCorrelationID(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": {}}`)
+
+	assert.Equal(t, response.Code, 400)
+	id := response.Header().Get(CorrelationIDHeader)
+	assert.NotEmpty(t, id)
+	assert.Contains(t, response.Body.String(), `"correlation_id":"`+id+`"`)
+	assert.NotContains(t, response.Body.String(), "Go struct field")
+}
+
+func TestCalculateFlightPath_ProblemDetails(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsAccepting(router, `{"flight_legs": [["SFO", "SFO"]]}`, "application/problem+json")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Equal(t, response.Header().Get("Content-Type"), "application/problem+json")
+	assert.JSONEq(t, response.Body.String(), `{
+		"type": "urn:flight-path-tracker:problem:same_departure_and_arrival",
+		"title": "Invalid flight path",
+		"status": 400,
+		"detail": "A flight leg cannot depart from and arrive at the same airport.",
+		"instance": "/flight_paths",
+		"code": "same_departure_and_arrival",
+		"retryable": false,
+		"correlation_id": "`+response.Header().Get(CorrelationIDHeader)+`"
+	}`)
+}
+
+func TestCalculateFlightPath_LegacyErrorFormatIsTheDefault(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	for _, accept := range []string{"", "*/*", "application/json", "application/json, application/problem+json"} {
+		response := postFlightPathsAccepting(router, `{"flight_legs": [["SFO", "SFO"]]}`, accept)
+
+		assert.Equal(t, response.Code, 400)
+		assert.Equal(t, response.Header().Get("Content-Type"), "application/json; charset=utf-8")
+		assert.Contains(t, response.Body.String(), `"error":true`)
+	}
+}
+
+func TestCalculateFlightPath_AllFieldErrorsAreReported(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	legs := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		legs = append(legs, fmt.Sprintf(`["A%c%c", "A%c%c"]`, 'A'+i/26, 'A'+i%26, 'A'+(i+1)/26, 'A'+(i+1)%26))
+	}
+	legs[36] = `["O5D", "ABL"]`
+	legs[40] = `["ABO"]`
+	legs[45] = `["ABT", ""]`
+
+	response := postFlightPaths(router, `{"flight_legs": [`+strings.Join(legs, ",")+`]}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Code, "invalid_airport_code")
+	assert.Equal(t, body.InvalidParams, []FieldError{
+		{
+			FieldLocation: FieldLocation{Path: "flight_legs[36][0]"},
+			Code:          "invalid_airport_code",
+			Value:         "O5D",
+			Message:       "departure must be a 3-letter IATA airport code",
+		},
+		{
+			FieldLocation: FieldLocation{Path: "flight_legs[40]"},
+			Code:          "invalid_flight_leg",
+			Value:         []interface{}{"ABO"},
+			Message: "Each flight leg must be a list with the departure and arrival airport codes, such as " +
+				`["SFO", "ORD"]; an object, such as {"departure": "SFO", "arrival": "ORD"}; or a string, such as "SFO-ORD".`,
+		},
+		{
+			FieldLocation: FieldLocation{Path: "flight_legs[45][1]"},
+			Code:          "missing_airport_code",
+			Value:         "",
+			Message:       "arrival is a required field",
+		},
+	})
+}
+
+func TestCalculateFlightPath_LocalizedErrors(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths",
+		strings.NewReader(`{"flight_legs": [["SFO", "ATL"], ["ATL", "G5O"], ["IND", "SFO"]]}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", "es-MX,es;q=0.9,en;q=0.8")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Equal(t, response.Header().Get("Content-Language"), "es")
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Message, "El código de aeropuerto debe ser un código IATA de 3 letras.")
+	assert.Equal(t, body.InvalidParams[0].Message, "arrival debe ser un código de aeropuerto IATA de 3 letras")
+}
+
+func TestCalculateFlightPath_FlightLegFormat(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router,
+		`{"flight_legs": ["ATL-EWR", {"departure": "SFO", "arrival": "ATL"}], "flight_leg_format": "string"}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{"origin":"SFO","destination":"EWR","flight_legs":["SFO-ATL","ATL-EWR"]}`)
+}
+
+func TestCalculateFlightPath_InvalidFlightLegFormat(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPaths(router, `{"flight_legs": [["SFO", "ATL"]], "flight_leg_format": "xml"}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Code, "invalid_flight_leg_format")
+	assert.Equal(t, body.InvalidParams[0].Path, "flight_leg_format")
+}
+
+func postFlightPathsCSV(router *gin.Engine, payload, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths", strings.NewReader(payload))
+	request.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCalculateFlightPath_CSV(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsCSV(router, "departure,arrival\nATL,EWR\nSFO,ATL\n", "text/csv")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Header().Get("Content-Type"), "text/csv; charset=utf-8")
+	assert.Equal(t, response.Body.String(), "departure,arrival\nSFO,ATL\nATL,EWR\n")
+}
+
+func TestCalculateFlightPath_CSVRequestJSONResponse(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsCSV(router, "departure,arrival\nATL,EWR\nSFO,ATL\n", "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(),
+		`{"origin":"SFO","destination":"EWR","flight_legs":[["SFO","ATL"],["ATL","EWR"]]}`)
+}
+
+func TestCalculateFlightPath_JSONRequestCSVResponse(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsAccepting(router,
+		`{"flight_legs": [{"departure": "ATL", "arrival": "EWR", "arrival_time": "2024-03-25T18:40:00Z"}, "SFO-ATL"]}`,
+		"text/csv")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Equal(t, response.Body.String(), "departure,arrival,departure_time,arrival_time\n"+
+		"SFO,ATL,,\n"+
+		"ATL,EWR,,2024-03-25T18:40:00Z\n")
+}
+
+func TestCalculateFlightPath_CSVFieldErrors(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsCSV(router, "departure,arrival,departure_time\n"+
+		"SFO,ATL,\n"+
+		"ATL,E5R,\n"+
+		"EWR\n"+
+		"IND,ORD,tomorrow\n", "")
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Code, "invalid_airport_code")
+	assert.Equal(t, body.InvalidParams, []FieldError{
+		{
+			FieldLocation: FieldLocation{Row: 3, Column: "arrival"},
+			Code:          "invalid_airport_code",
+			Value:         "E5R",
+			Message:       "arrival must be a 3-letter IATA airport code",
+		},
+		{
+			FieldLocation: FieldLocation{Row: 4},
+			Code:          "invalid_csv_row",
+			Message:       "Each CSV row must have one value for each column of the header.",
+		},
+		{
+			FieldLocation: FieldLocation{Row: 5, Column: "departure_time"},
+			Code:          "invalid_flight_leg_time",
+			Value:         "tomorrow",
+			Message:       "Departure and arrival times must be in the RFC 3339 format, such as 2024-03-25T08:05:00-07:00.",
+		},
+	})
+}
+
+func TestCalculateFlightPath_CSVHeaderErrors(t *testing.T) {
+	c := DefaultConfig()
+	c.StrictFlightLegFields = true
+	router := newTestRouter(t, c)
+
+	response := postFlightPathsCSV(router, "gate,arrival_time,departure_time,arrival_time\nA1,,,\n", "")
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+
+	var locations []FieldLocation
+	for _, param := range body.InvalidParams {
+		locations = append(locations, param.FieldLocation)
+	}
+	assert.Equal(t, locations, []FieldLocation{
+		{Row: 1, Column: "departure"},
+		{Row: 1, Column: "arrival"},
+		{Row: 1, Column: "arrival_time"},
+		{Row: 1, Column: "gate"},
+	})
+}
+
+func TestCalculateFlightPath_MalformedCSV(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsCSV(router, "departure,arrival\n\"SFO,ATL\n", "")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"malformed_request"`)
+}
+
+// BenchmarkCalculateFlightPath_HTTP goes through the gin router, from decoding the request body to encoding the
+// flight path, for both the buffered and the streamed decoding.
+func BenchmarkCalculateFlightPath_HTTP(b *testing.B) {
+	log.SetLevel(logrus.WarnLevel)
+	b.Cleanup(func() {
+		log.SetLevel(logrus.InfoLevel)
+	})
+
+	streamed := DefaultConfig()
+	streamed.StreamingThreshold = 1
+
+	for _, length := range []int{10, 1_000, generator.PlainAirportCodes - 1} {
+		body, err := generator.RequestBody(generator.New(42).Itinerary(length), model.FlightLegFormatArray)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, bm := range []struct {
+			name   string
+			config Config
+		}{{"buffered", DefaultConfig()}, {"streamed", streamed}} {
+			b.Run(fmt.Sprintf("%v/%v", bm.name, length), func(b *testing.B) {
+				router := newTestRouter(b, bm.config)
+				b.SetBytes(int64(len(body)))
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					request := httptest.NewRequest(http.MethodPost, "/flight_paths", bytes.NewReader(body))
+					request.Header.Set("Content-Type", "application/json")
+
+					recorder := httptest.NewRecorder()
+					router.ServeHTTP(recorder, request)
+
+					if recorder.Code != 200 {
+						b.Fatalf("unexpected status %v: %v", recorder.Code, recorder.Body)
+					}
+				}
+			})
+		}
+	}
+}
@@ -1,10 +1,59 @@
 package api
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/network"
 	"github.com/felipead/flight-path-tracker/pkg/validator"
 )
 
+var config = DefaultConfig()
+
+// routes is the route network loaded from Config.RoutesFile, or nil if there's none
+var routes *network.Routes
+
+// airports is the airports dataset loaded from Config.AirportsFile, or nil if there's none
+var airports *network.Airports
+
+// emissionsMethod is the method of emissions estimates loaded from Config.EmissionsMethodFile, or the default one
+var emissionsMethod = domain.DefaultEmissionsMethod
+
 // Init is supposed to be called before the server starts serving API requests
-func Init() error {
+func Init(c Config) error {
+	config = c
+
+	routes = nil
+	if c.RoutesFile != "" {
+		loaded, err := network.LoadRoutesFile(c.RoutesFile)
+		if err != nil {
+			return err
+		}
+		routes = loaded
+	}
+
+	airports = nil
+	if c.AirportsFile != "" {
+		loaded, err := network.LoadAirportsFile(c.AirportsFile)
+		if err != nil {
+			return err
+		}
+		airports = loaded
+	}
+
+	emissionsMethod = domain.DefaultEmissionsMethod
+	if c.EmissionsMethodFile != "" {
+		data, err := os.ReadFile(c.EmissionsMethodFile)
+		if err != nil {
+			return err
+		}
+		method, err := domain.ParseEmissionsMethod(data)
+		if err != nil {
+			return fmt.Errorf("%v: %w", c.EmissionsMethodFile, err)
+		}
+		emissionsMethod = method
+	}
+
 	return validator.InitValidator()
 }
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"golang.org/x/text/language"
+)
+
+const defaultLanguage = "en"
+
+// supportedLanguages must list the default language first, since it is the fallback of the matcher.
+var supportedLanguages = []language.Tag{
+	language.English,
+	language.Spanish,
+	language.Portuguese,
+}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+// negotiateLanguage picks the supported language that best matches the Accept-Language header, such as "es" for
+// "es-MX,es;q=0.9". It falls back to English if the header is missing, malformed, or no language matches.
+func negotiateLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return defaultLanguage
+	}
+
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return defaultLanguage
+	}
+
+	base, _ := supportedLanguages[index].Base()
+	return base.String()
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		wantLanguage   string
+	}{
+		{"", "en"},
+		{"en-US", "en"},
+		{"es", "es"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"pt-BR", "pt"},
+		{"fr-FR,pt;q=0.5", "pt"},
+		{"de-DE", "en"},
+		{"ja,es;q=0.1", "es"},
+		{"*", "en"},
+		{";;;;malformed", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, negotiateLanguage(tt.acceptLanguage), tt.wantLanguage)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	errMissingFragments      = errors.New("at least one fragment must be provided")
+	errMissingFragmentSource = errors.New("the fragment has no source")
+)
+
+type MergeFlightPathRequest struct {
+	Fragments []domain.Fragment `json:"fragments"`
+
+	// FlightLegFormat is how the flight legs of the response should be formatted, like in DiffFlightPathsRequest
+	FlightLegFormat model.FlightLegFormat `json:"flight_leg_format"`
+
+	// fragments keeps the raw fragments until validate decodes them
+	fragments []json.RawMessage
+}
+
+// UnmarshalJSON leaves the fragments to validate, like DiffFlightPathsRequest.UnmarshalJSON does with its flight
+// paths.
+func (r *MergeFlightPathRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Fragments       []json.RawMessage     `json:"fragments"`
+		FlightLegFormat model.FlightLegFormat `json:"flight_leg_format"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.fragments = raw.Fragments
+	r.FlightLegFormat = raw.FlightLegFormat
+	return nil
+}
+
+// validate decodes and validates every fragment. Their field errors are located under the "fragments" field of the
+// request body, such as "fragments[2].flight_legs[0][1]".
+func (r *MergeFlightPathRequest) validate() error {
+	if err := validateFlightLegFormat(r.FlightLegFormat); err != nil {
+		return err
+	}
+
+	if len(r.fragments) == 0 {
+		return NewValidationError(fieldErrors{{
+			location: FieldLocation{Path: "fragments"},
+			leg:      -1,
+			code:     CodeMissingFragments,
+			err:      errMissingFragments,
+		}})
+	}
+
+	var errs fieldErrors
+	var flightLegCount int
+	r.Fragments = make([]domain.Fragment, len(r.fragments))
+
+	for i, raw := range r.fragments {
+		field := fmt.Sprintf("fragments[%v]", i)
+
+		var source struct {
+			Source string `json:"source"`
+		}
+		var request CalculateFlightPathRequest
+		decodeErr := json.Unmarshal(raw, &source)
+		if decodeErr == nil {
+			decodeErr = json.Unmarshal(raw, &request)
+		}
+
+		if decodeErr == nil && source.Source == "" {
+			errs = append(errs, &fieldError{
+				location: FieldLocation{Path: field + ".source"},
+				leg:      -1,
+				code:     CodeMissingFragmentSource,
+				err:      errMissingFragmentSource,
+			})
+		}
+
+		flightLegCount += len(request.FlightLegs)
+		if flightLegCount > config.MaxFlightLegs {
+			return NewValidationError(tooManyFlightLegsError())
+		}
+
+		fragmentErrors, err := validateSubRequest(field, &request, decodeErr)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, fragmentErrors...)
+
+		r.Fragments[i] = domain.Fragment{Source: source.Source, FlightLegs: request.FlightLegs}
+	}
+
+	if len(errs) > 0 {
+		return NewValidationError(errs)
+	}
+	return nil
+}
+
+// MergeFlightPath calculates a single flight path out of the flight legs given by multiple sources, such as the
+// airline and the travel agency. Identical flight legs are merged, and the response tells which sources gave each
+// one of them.
+func MergeFlightPath(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.MaxRequestSize)
+
+	var request MergeFlightPathRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+	if err := request.validate(); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"Fragments": request.Fragments,
+	}).Info("Merging flight path")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.CalculationTimeout)
+	defer cancel()
+
+	flightPath, err := domain.CalculateMergedFlightPathContext(ctx, request.Fragments)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	detectAnomalies(&flightPath.FlightPath)
+	describeFindings(&flightPath.FlightPath, negotiateLanguage(c.GetHeader("Accept-Language")))
+
+	flightPath.FlightLegFormat = request.FlightLegFormat
+	c.JSON(200, flightPath)
+}
+
+// FlightLegConflict is the public representation of flight legs, given by different sources, that contradict each
+// other. Code is either multiple_outbound_legs or multiple_inbound_legs.
+type FlightLegConflict struct {
+	Airport    model.AirportCode  `json:"airport"`
+	Code       string             `json:"code"`
+	FlightLegs []SourcedFlightLeg `json:"flight_legs"`
+}
+
+type SourcedFlightLeg struct {
+	// FlightLeg is always an object, so that the times of the flight leg are shown as well
+	FlightLeg json.RawMessage `json:"flight_leg"`
+	Sources   []string        `json:"sources"`
+}
+
+func newFlightLegConflictResponses(err error) []FlightLegConflict {
+	var conflictError *domain.MergeConflictError
+	if !errors.As(err, &conflictError) {
+		return nil
+	}
+
+	responses := make([]FlightLegConflict, 0, len(conflictError.Conflicts))
+	for _, conflict := range conflictError.Conflicts {
+		legs := make([]SourcedFlightLeg, 0, len(conflict.FlightLegs))
+		for _, leg := range conflict.FlightLegs {
+			data, err := leg.MarshalJSONFormat(model.FlightLegFormatObject)
+			if err != nil {
+				continue
+			}
+			legs = append(legs, SourcedFlightLeg{FlightLeg: data, Sources: leg.Sources})
+		}
+
+		responses = append(responses, FlightLegConflict{
+			Airport:    conflict.Airport,
+			Code:       ErrorCode(conflict.Err),
+			FlightLegs: legs,
+		})
+	}
+	return responses
+}
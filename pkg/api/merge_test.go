@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMergeTestRouter(t *testing.T) *gin.Engine {
+	router := newTestRouter(t, DefaultConfig())
+	router.POST("/flight_paths:method", CustomMethods(map[string]gin.HandlerFunc{
+		"merge": MergeFlightPath,
+	}))
+	return router
+}
+
+func TestMergeFlightPath(t *testing.T) {
+	router := newMergeTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:merge", `{
+		"fragments": [
+			{"source": "airline", "flight_legs": [["SFO", "ATL"], ["ATL", "GSO"]]},
+			{"source": "agency", "flight_legs": ["ATL-GSO", {"departure": "GSO", "arrival": "IND"}]}
+		],
+		"flight_leg_format": "string"
+	}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "SFO",
+		"destination": "IND",
+		"flight_legs": ["SFO-ATL", "ATL-GSO", "GSO-IND"],
+		"flight_leg_sources": [["airline"], ["airline", "agency"], ["agency"]]
+	}`)
+}
+
+func TestMergeFlightPath_Anomalies(t *testing.T) {
+	router := newMergeTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:merge", `{
+		"fragments": [
+			{"source": "airline", "flight_legs": [
+				{"departure": "SFO", "arrival": "ATL", "arrival_time": "2024-03-25T12:00:00Z"}
+			]},
+			{"source": "agency", "flight_legs": [
+				{"departure": "ATL", "arrival": "GSO", "departure_time": "2024-03-25T11:30:00Z"}
+			]}
+		],
+		"flight_leg_format": "string"
+	}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "SFO",
+		"destination": "GSO",
+		"flight_legs": ["SFO-ATL", "ATL-GSO"],
+		"flight_leg_sources": [["airline"], ["agency"]],
+		"anomalies": [{
+			"code": "overlapping_flight_legs",
+			"severity": "high",
+			"message": "A flight leg departs before the one before it arrives, so the traveler would be in two places at once.",
+			"flight_legs": ["SFO-ATL", "ATL-GSO"]
+		}]
+	}`)
+}
+
+func TestMergeFlightPath_Conflicts(t *testing.T) {
+	router := newMergeTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:merge", `{
+		"fragments": [
+			{"source": "airline", "flight_legs": [
+				{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:00:00-07:00"},
+				["ATL", "GSO"]
+			]},
+			{"source": "agency", "flight_legs": [
+				{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:00:00-07:00"},
+				["ATL", "IND"]
+			]}
+		]
+	}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body struct {
+		Code      string          `json:"code"`
+		Conflicts json.RawMessage `json:"conflicts"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, body.Code, CodeConflictingFlightLegs)
+	assert.JSONEq(t, string(body.Conflicts), `[
+		{
+			"airport": "ATL",
+			"code": "multiple_outbound_legs",
+			"flight_legs": [
+				{"flight_leg": {"departure": "ATL", "arrival": "GSO"}, "sources": ["airline"]},
+				{"flight_leg": {"departure": "ATL", "arrival": "IND"}, "sources": ["agency"]}
+			]
+		}
+	]`)
+}
+
+func TestMergeFlightPath_FieldErrorsOfEveryFragment(t *testing.T) {
+	router := newMergeTestRouter(t)
+
+	response := postDiff(router, "/flight_paths:merge", `{
+		"fragments": [
+			{"source": "airline", "flight_legs": [["SFO", "AT1"]]},
+			{"flight_legs": [["ATL", "GSO"]]},
+			{"source": "agency", "flight_legs": [{"departure": "GSO"}]}
+		]
+	}`)
+
+	assert.Equal(t, response.Code, 400)
+
+	var body ErrorResponse
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+
+	var paths, codes []string
+	for _, param := range body.InvalidParams {
+		paths = append(paths, param.Path)
+		codes = append(codes, param.Code)
+	}
+	assert.Equal(t, paths, []string{
+		"fragments[0].flight_legs[0][1]", "fragments[1].source", "fragments[2].flight_legs[0].arrival",
+	})
+	assert.Equal(t, codes, []string{CodeInvalidAirportCode, CodeMissingFragmentSource, CodeMissingAirportCode})
+}
+
+func TestMergeFlightPath_InvalidRequest(t *testing.T) {
+	router := newMergeTestRouter(t)
+
+	tests := []struct {
+		payload  string
+		wantCode string
+	}{
+		{`{"fragments": `, CodeMalformedRequest},
+		{`{"fragments": {}}`, CodeMalformedRequest},
+		{`{"fragments": [3]}`, CodeMalformedRequest},
+		{`{"fragments": []}`, CodeMissingFragments},
+		{`{"fragments": [{"source": "airline"}]}`, CodeMissingFlightLegs},
+		{`{"fragments": [{"source": "airline", "flight_legs": [["SFO", "ATL"]]}], "flight_leg_format": "xml"}`,
+			CodeInvalidFlightLegFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.payload, func(t *testing.T) {
+			response := postDiff(router, "/flight_paths:merge", test.payload)
+
+			assert.Equal(t, response.Code, 400)
+			assert.Contains(t, response.Body.String(), `"code":"`+test.wantCode+`"`)
+		})
+	}
+}
+
+func TestMergeFlightPath_TooManyFlightLegs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.MaxFlightLegs = 2
+	router := newTestRouter(t, cfg)
+	router.POST("/flight_paths:method", CustomMethods(map[string]gin.HandlerFunc{
+		"merge": MergeFlightPath,
+	}))
+
+	response := postDiff(router, "/flight_paths:merge", `{
+		"fragments": [
+			{"source": "airline", "flight_legs": [["SFO", "ATL"], ["ATL", "GSO"]]},
+			{"source": "agency", "flight_legs": [["GSO", "IND"]]}
+		]
+	}`)
+
+	assert.Equal(t, response.Code, 413)
+	assert.Contains(t, response.Body.String(), `"code":"too_many_flight_legs"`)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// parseCalculationOptions reads the options of the flight path calculation from the query parameters of the request.
+// Duplicate flight legs are rejected by default, unless told otherwise by the duplicates parameter. If a route network
+// was loaded, flight legs are checked against it, and it ranks the suggestions for disconnected flight paths.
+func parseCalculationOptions(c *gin.Context) (domain.CalculationOptions, error) {
+	var options domain.CalculationOptions
+	if routes != nil {
+		options.Routes = routes
+		options.StrictRoutes = config.StrictRoutes
+		options.GapRanker = routes
+	}
+
+	duplicates, err := parseDuplicatePolicy(c)
+	if err != nil {
+		return options, err
+	}
+	options.Duplicates = duplicates
+
+	return options, nil
+}
+
+// detectAnomalies checks the times of the flight path for anomalies. The speeds of its flight legs are only checked if
+// an airports dataset was loaded.
+func detectAnomalies(flightPath *model.FlightPath) {
+	var locator domain.AirportLocator
+	if airports != nil {
+		locator = airports
+	}
+	flightPath.Anomalies = domain.DetectAnomalies(flightPath.FlightLegs, locator, domain.DefaultAnomalyThresholds)
+}
+
+// describeFindings gives each warning and anomaly of the flight path the public code and message of its error, in the
+// given language.
+func describeFindings(flightPath *model.FlightPath, language string) {
+	for i := range flightPath.Warnings {
+		describeFinding(&flightPath.Warnings[i].Finding, language)
+	}
+	for i := range flightPath.Anomalies {
+		describeFinding(&flightPath.Anomalies[i].Finding, language)
+	}
+}
+
+func describeFinding(finding *model.Finding, language string) {
+	finding.Code = ErrorCode(finding.Err)
+	finding.Message = PublicMessage(finding.Code, language)
+}
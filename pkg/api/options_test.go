@@ -0,0 +1,84 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unknownRoutePayload = `{"flight_legs": [["GSO", "IND"], ["SFO", "ATL"], ["ATL", "GSO"]], "flight_leg_format": "string"}`
+
+func routesConfig(strict bool) Config {
+	c := DefaultConfig()
+	c.RoutesFile = "../../data/routes.dat"
+	c.StrictRoutes = strict
+	return c
+}
+
+func TestCalculateFlightPath_UnknownRoutesAreWarnings(t *testing.T) {
+	router := newTestRouter(t, routesConfig(false))
+
+	response := postDuplicates(router, "", unknownRoutePayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "SFO",
+		"destination": "IND",
+		"flight_legs": ["SFO-ATL", "ATL-GSO", "GSO-IND"],
+		"warnings": [{
+			"code": "unknown_route",
+			"message": "No airline is known to fly nonstop between the airports of the flight leg.",
+			"flight_leg": "GSO-IND"
+		}]
+	}`)
+}
+
+func TestCalculateFlightPath_ReturnTripLeavesOutUnknownRoutes(t *testing.T) {
+	router := newTestRouter(t, routesConfig(false))
+
+	response := postDuplicates(router, "return_trip=true", unknownRoutePayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"origin": "IND",
+		"destination": "SFO",
+		"flight_legs": ["IND-GSO", "GSO-ATL", "ATL-SFO"]
+	}`)
+}
+
+func TestCalculateFlightPath_StrictRoutes(t *testing.T) {
+	router := newTestRouter(t, routesConfig(true))
+
+	response := postDuplicates(router, "", unknownRoutePayload, "")
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"unknown_route"`)
+}
+
+func TestCalculateFlightPath_StrictRoutesWithLowerCaseAirportCodes(t *testing.T) {
+	router := newTestRouter(t, routesConfig(true))
+
+	response := postDuplicates(router, "", `{"flight_legs": [["atl", "gso"], ["sfo", "atl"]]}`, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.NotContains(t, response.Body.String(), "warnings")
+}
+
+func TestCalculateFlightPath_WithoutRoutes(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postDuplicates(router, "", unknownRoutePayload, "")
+
+	assert.Equal(t, response.Code, 200)
+	assert.NotContains(t, response.Body.String(), "warnings")
+}
+
+func TestInit_MissingRoutesFile(t *testing.T) {
+	c := DefaultConfig()
+	c.RoutesFile = "../../data/missing.dat"
+	t.Cleanup(func() {
+		config = DefaultConfig()
+	})
+
+	assert.Error(t, Init(c))
+}
@@ -0,0 +1,44 @@
+package api
+
+const (
+	MIMEProblemJSON = "application/problem+json"
+
+	// ProblemTypePrefix is prepended to the error code to form the "type" of a problem document.
+	ProblemTypePrefix = "urn:flight-path-tracker:problem:"
+)
+
+// Problem is an error response rendered as an RFC 7807 problem details document. Members after Instance are
+// extension members.
+type Problem struct {
+	Type                string               `json:"type"`
+	Title               string               `json:"title"`
+	Status              int                  `json:"status"`
+	Detail              string               `json:"detail"`
+	Instance            string               `json:"instance,omitempty"`
+	Code                string               `json:"code"`
+	Retryable           bool                 `json:"retryable"`
+	CorrelationID       string               `json:"correlation_id,omitempty"`
+	InvalidParams       []FieldError         `json:"invalid_params,omitempty"`
+	Conflicts           []FlightLegConflict  `json:"conflicts,omitempty"`
+	SuggestedFlightLegs []SuggestedFlightLeg `json:"suggested_flight_legs,omitempty"`
+}
+
+// NewProblem is the RFC 7807 counterpart of NewErrorResponse. The instance is the URI of the request that failed.
+func NewProblem(err error, correlationID, instance, language string) *Problem {
+	code := ErrorCode(err)
+	entry := errorCatalogue[code]
+
+	return &Problem{
+		Type:                ProblemTypePrefix + code,
+		Title:               entry.class.Title(),
+		Status:              entry.class.Status(),
+		Detail:              PublicMessage(code, language),
+		Instance:            instance,
+		Code:                code,
+		Retryable:           entry.class.Retryable(),
+		CorrelationID:       correlationID,
+		InvalidParams:       newFieldErrorResponses(err, language),
+		Conflicts:           newFlightLegConflictResponses(err),
+		SuggestedFlightLegs: newSuggestedFlightLegResponses(err),
+	}
+}
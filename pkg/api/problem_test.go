@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+	"github.com/felipead/flight-path-tracker/pkg/validator"
+)
+
+func TestNewProblem(t *testing.T) {
+	err := fmt.Errorf("%w; %w", domain.ErrInvalidFlightPath, domain.ErrStartNotFound)
+
+	problem := NewProblem(err, "abc", "/flight_paths", "en")
+
+	assert.Equal(t, problem, &Problem{
+		Type:          "urn:flight-path-tracker:problem:flight_path_loop",
+		Title:         "Invalid flight path",
+		Status:        400,
+		Detail:        "The flight legs form a loop, so the flight path has no origin or destination.",
+		Instance:      "/flight_paths",
+		Code:          "flight_path_loop",
+		Retryable:     false,
+		CorrelationID: "abc",
+	})
+}
+
+func TestNewProblem_InvalidParams(t *testing.T) {
+	assert.NoError(t, validator.InitValidator())
+
+	err := validator.GetValidator().Struct(&CalculateFlightPathRequest{
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "", Arrival: "GSO"},
+			{Departure: "GSO", Arrival: "I5D"},
+		},
+	})
+
+	problem := NewProblem(NewValidationError(err), "abc", "/flight_paths", "en")
+
+	assert.Equal(t, problem.Status, 400)
+	assert.Equal(t, problem.InvalidParams, []FieldError{
+		{
+			FieldLocation: FieldLocation{Path: "flight_legs[1][0]"},
+			Code:          "missing_airport_code",
+			Value:         model.AirportCode(""),
+			Message:       "departure is a required field",
+		},
+		{
+			FieldLocation: FieldLocation{Path: "flight_legs[2][1]"},
+			Code:          "invalid_airport_code",
+			Value:         model.AirportCode("I5D"),
+			Message:       "arrival must be a 3-letter IATA airport code",
+		},
+	})
+}
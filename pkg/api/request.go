@@ -1,9 +1,157 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	goValidator "github.com/go-playground/validator/v10"
+
 	"github.com/felipead/flight-path-tracker/pkg/model"
+	"github.com/felipead/flight-path-tracker/pkg/validator"
 )
 
+// ErrTooManyFlightLegs is returned for flight paths with more flight legs than Config.MaxFlightLegs
+var ErrTooManyFlightLegs = errors.New("too many flight legs")
+
 type CalculateFlightPathRequest struct {
 	FlightLegs []model.FlightLeg `json:"flight_legs" validate:"required,notblank,dive"`
+
+	// FlightLegFormat is how the flight legs of the response should be formatted. It defaults to arrays.
+	FlightLegFormat model.FlightLegFormat `json:"flight_leg_format" validate:"omitempty,oneof=array object string"`
+
+	// flightLegFormats keeps the format each flight leg was given in, to locate their errors in a JSON body
+	flightLegFormats []model.FlightLegFormat
+	// csvRows keeps the row number of each flight leg, to locate their errors in a CSV body
+	csvRows []int
+}
+
+// UnmarshalJSON decodes every flight leg, even after one of them fails, so that the errors of all invalid flight
+// legs are returned together.
+func (r *CalculateFlightPathRequest) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FlightLegs      []json.RawMessage     `json:"flight_legs"`
+		FlightLegFormat model.FlightLegFormat `json:"flight_leg_format"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var errs fieldErrors
+
+	r.FlightLegFormat = raw.FlightLegFormat
+	r.FlightLegs = nil
+	r.flightLegFormats = nil
+	r.csvRows = nil
+	if raw.FlightLegs != nil {
+		r.FlightLegs = make([]model.FlightLeg, len(raw.FlightLegs))
+		r.flightLegFormats = make([]model.FlightLegFormat, len(raw.FlightLegs))
+	}
+
+	for i, rawLeg := range raw.FlightLegs {
+		leg, format, err := model.UnmarshalFlightLeg(rawLeg, config.StrictFlightLegFields)
+		if err != nil {
+			errs = append(errs, newFlightLegFieldError(i, err))
+		}
+		r.FlightLegs[i] = leg
+		r.flightLegFormats[i] = format
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateFlightLegFormat validates the format of the flight legs of the response, for requests that decode and
+// validate their flight paths on their own, such as DiffFlightPathsRequest. It checks the same constraint as the
+// struct tag of CalculateFlightPathRequest.FlightLegFormat.
+func validateFlightLegFormat(format model.FlightLegFormat) error {
+	request := CalculateFlightPathRequest{FlightLegFormat: format}
+	err := validator.GetValidator().StructPartial(&request, "FlightLegFormat")
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors goValidator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	return NewValidationError(newValidationFieldErrors(validationErrors, request.locate))
+}
+
+// validateSubRequest validates a flight path that is part of a larger request, such as the "before" flight path of
+// DiffFlightPathsRequest, and locates its field errors under the given field of the request body, such as
+// "before.flight_legs[0][1]". Any other error is returned as is.
+func validateSubRequest(field string, request *CalculateFlightPathRequest, decodeErr error) (fieldErrors, error) {
+	err := validateRequest(request, decodeErr)
+	if err == nil {
+		return nil, nil
+	}
+
+	var errs fieldErrors
+	if !errors.As(err, &errs) {
+		return nil, err
+	}
+	for _, e := range errs {
+		e.location.Path = field + "." + e.location.Path
+	}
+	return errs, nil
+}
+
+var requestFieldPaths = map[string]string{
+	"CalculateFlightPathRequest.FlightLegs":      "flight_legs",
+	"CalculateFlightPathRequest.FlightLegFormat": "flight_leg_format",
+}
+
+var flightLegNamespace = regexp.MustCompile(`^CalculateFlightPathRequest\.FlightLegs\[(\d+)]\.(Departure|Arrival)$`)
+
+// locate converts the namespace of a struct field, such as "CalculateFlightPathRequest.FlightLegs[36].Arrival", into
+// the location of the corresponding value in the request body. It also returns the index of the flight leg that holds
+// the value, or -1 if there's none.
+func (r *CalculateFlightPathRequest) locate(namespace string) (FieldLocation, int) {
+	match := flightLegNamespace.FindStringSubmatch(namespace)
+	if match == nil {
+		if r.csvRows != nil {
+			return FieldLocation{}, -1
+		}
+		if path, ok := requestFieldPaths[namespace]; ok {
+			return FieldLocation{Path: path}, -1
+		}
+		return FieldLocation{Path: namespace}, -1
+	}
+
+	index, _ := strconv.Atoi(match[1])
+	field := strings.ToLower(match[2])
+
+	if r.csvRows != nil && index < len(r.csvRows) {
+		return FieldLocation{Row: r.csvRows[index], Column: field}, index
+	}
+
+	var format model.FlightLegFormat
+	if index < len(r.flightLegFormats) {
+		format = r.flightLegFormats[index]
+	}
+	return FieldLocation{Path: flightLegFieldPath(index, format, field)}, index
+}
+
+// flightLegFieldPath depends on the format the flight leg was given in: "flight_legs[36][1]" for arrays,
+// "flight_legs[36].arrival" for objects, or just "flight_legs[36]" for strings.
+func flightLegFieldPath(index int, format model.FlightLegFormat, field string) string {
+	leg := fmt.Sprintf("flight_legs[%v]", index)
+
+	switch format {
+	case model.FlightLegFormatObject:
+		return leg + "." + field
+	case model.FlightLegFormatString:
+		return leg
+	default:
+		if field == "departure" {
+			return leg + "[0]"
+		}
+		return leg + "[1]"
+	}
 }
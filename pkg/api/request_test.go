@@ -135,9 +135,9 @@ func TestValidateCalculateFlightPathRequest_ValidPayload(t *testing.T) {
 
 	request := &CalculateFlightPathRequest{
 		FlightLegs: []model.FlightLeg{
-			{"ORD", "JFK"},
-			{"SFO", "ORD"},
-			{"JFK", "LHR"},
+			{Departure: "ORD", Arrival: "JFK"},
+			{Departure: "SFO", Arrival: "ORD"},
+			{Departure: "JFK", Arrival: "LHR"},
 		},
 	}
 
@@ -155,7 +155,7 @@ func TestValidateCalculateFlightPathRequest_EmptyList(t *testing.T) {
 	validator := validator.GetValidator()
 	assert.ErrorContains(
 		t, validator.Struct(request),
-		"validation for 'FlightLegs' failed on the 'notblank' tag",
+		"validation for 'flight_legs' failed on the 'notblank' tag",
 	)
 }
 
@@ -164,16 +164,16 @@ func TestValidateCalculateFlightPathRequest_EmptyDepartureAirportCode(t *testing
 
 	request := &CalculateFlightPathRequest{
 		FlightLegs: []model.FlightLeg{
-			{"ORD", "JFK"},
-			{"", "ORD"},
-			{"JFK", "LHR"},
+			{Departure: "ORD", Arrival: "JFK"},
+			{Departure: "", Arrival: "ORD"},
+			{Departure: "JFK", Arrival: "LHR"},
 		},
 	}
 
 	validator := validator.GetValidator()
 	assert.ErrorContains(
 		t, validator.Struct(request),
-		"Error:Field validation for 'Departure' failed on the 'required' tag",
+		"Error:Field validation for 'departure' failed on the 'required' tag",
 	)
 }
 
@@ -182,16 +182,16 @@ func TestValidateCalculateFlightPathRequest_InvalidDepartureAirportCode(t *testi
 
 	request := &CalculateFlightPathRequest{
 		FlightLegs: []model.FlightLeg{
-			{"ORD", "JFK"},
-			{"SSFF5", "ORD"},
-			{"JFK", "LHR"},
+			{Departure: "ORD", Arrival: "JFK"},
+			{Departure: "SSFF5", Arrival: "ORD"},
+			{Departure: "JFK", Arrival: "LHR"},
 		},
 	}
 
 	validator := validator.GetValidator()
 	assert.ErrorContains(
 		t, validator.Struct(request),
-		"Error:Field validation for 'Departure' failed on the 'airport_code' tag",
+		"Error:Field validation for 'departure' failed on the 'airport_code' tag",
 	)
 }
 
@@ -200,16 +200,16 @@ func TestValidateCalculateFlightPathRequest_EmptyArrivalAirportCode(t *testing.T
 
 	request := &CalculateFlightPathRequest{
 		FlightLegs: []model.FlightLeg{
-			{"ORD", "JFK"},
-			{"SFO", ""},
-			{"JFK", "LHR"},
+			{Departure: "ORD", Arrival: "JFK"},
+			{Departure: "SFO", Arrival: ""},
+			{Departure: "JFK", Arrival: "LHR"},
 		},
 	}
 
 	validator := validator.GetValidator()
 	assert.ErrorContains(
 		t, validator.Struct(request),
-		"Error:Field validation for 'Arrival' failed on the 'required' tag",
+		"Error:Field validation for 'arrival' failed on the 'required' tag",
 	)
 }
 
@@ -218,15 +218,132 @@ func TestValidateCalculateFlightPathRequest_InvalidArrivalAirportCode(t *testing
 
 	request := &CalculateFlightPathRequest{
 		FlightLegs: []model.FlightLeg{
-			{"ORD", "JFK"},
-			{"SFO", "555"},
-			{"JFK", "LHR"},
+			{Departure: "ORD", Arrival: "JFK"},
+			{Departure: "SFO", Arrival: "555"},
+			{Departure: "JFK", Arrival: "LHR"},
 		},
 	}
 
 	validator := validator.GetValidator()
 	assert.ErrorContains(
 		t, validator.Struct(request),
-		"Error:Field validation for 'Arrival' failed on the 'airport_code' tag",
+		"Error:Field validation for 'arrival' failed on the 'airport_code' tag",
 	)
 }
+
+func TestUnmarshalCalculateFlightPathRequest_AllInvalidFlightLegsAreReported(t *testing.T) {
+	payload := `{
+	"flight_legs": [
+		["IND", "EWR"],
+		["SFO"],
+		["GSO", "IND"],
+		[555, "ATL"],
+		{"departure": 5, "arrival": "ATL"}
+	]
+}`
+	var request CalculateFlightPathRequest
+	err := json.Unmarshal([]byte(payload), &request)
+	assert.EqualError(t, err, "unable to unmarshal flight leg: JSON array contains 1 entries; "+
+		"unable to unmarshal flight leg: departure code is not a string; "+
+		"unable to unmarshal flight leg: departure code is not a string")
+	assert.ErrorIs(t, err, model.ErrInvalidFlightLeg)
+
+	var errs fieldErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, errs[0].location.Path, "flight_legs[1]")
+	assert.Equal(t, errs[1].location.Path, "flight_legs[3][0]")
+	assert.Equal(t, errs[1].value, float64(555))
+	assert.Equal(t, errs[2].location.Path, "flight_legs[4].departure")
+
+	assert.Equal(t, request.FlightLegs[2], model.FlightLeg{Departure: "GSO", Arrival: "IND"})
+}
+
+func TestUnmarshalCalculateFlightPathRequest_AlternativeFlightLegFormats(t *testing.T) {
+	payload := `{
+	"flight_legs": [
+		"IND-EWR",
+		{"departure": "SFO", "arrival": "ATL", "carrier": "DL"},
+		["GSO", "IND"],
+		{"departure": "ATL", "arrival": "GSO"}
+	],
+	"flight_leg_format": "object"
+}`
+	var request CalculateFlightPathRequest
+	err := json.Unmarshal([]byte(payload), &request)
+	assert.NoError(t, err)
+
+	assert.Equal(t, request.FlightLegFormat, model.FlightLegFormatObject)
+	assert.Equal(t, request.FlightLegs, []model.FlightLeg{
+		{Departure: "IND", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "GSO", Arrival: "IND"},
+		{Departure: "ATL", Arrival: "GSO"},
+	})
+}
+
+func TestUnmarshalCalculateFlightPathRequest_StrictFlightLegFields(t *testing.T) {
+	config.StrictFlightLegFields = true
+	t.Cleanup(func() {
+		config = DefaultConfig()
+	})
+
+	payload := `{
+	"flight_legs": [
+		{"departure": "SFO", "arrival": "ATL", "carrier": "DL"},
+		{"departure": "ATL", "arrival": "GSO"}
+	]
+}`
+	var request CalculateFlightPathRequest
+	err := json.Unmarshal([]byte(payload), &request)
+	assert.EqualError(t, err, `unable to unmarshal flight leg: unknown flight leg field "carrier"`)
+
+	var errs fieldErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, errs[0].location.Path, "flight_legs[0].carrier")
+	assert.Equal(t, errs[0].code, CodeUnknownFlightLegField)
+}
+
+func TestCalculateFlightPathRequest_Locate(t *testing.T) {
+	request := &CalculateFlightPathRequest{
+		flightLegFormats: []model.FlightLegFormat{
+			model.FlightLegFormatArray,
+			model.FlightLegFormatObject,
+			model.FlightLegFormatString,
+		},
+	}
+
+	tests := []struct {
+		namespace    string
+		wantLocation FieldLocation
+		wantLeg      int
+	}{
+		{"CalculateFlightPathRequest.FlightLegs", FieldLocation{Path: "flight_legs"}, -1},
+		{"CalculateFlightPathRequest.FlightLegFormat", FieldLocation{Path: "flight_leg_format"}, -1},
+		{"CalculateFlightPathRequest.FlightLegs[0].Departure", FieldLocation{Path: "flight_legs[0][0]"}, 0},
+		{"CalculateFlightPathRequest.FlightLegs[0].Arrival", FieldLocation{Path: "flight_legs[0][1]"}, 0},
+		{"CalculateFlightPathRequest.FlightLegs[1].Departure", FieldLocation{Path: "flight_legs[1].departure"}, 1},
+		{"CalculateFlightPathRequest.FlightLegs[1].Arrival", FieldLocation{Path: "flight_legs[1].arrival"}, 1},
+		{"CalculateFlightPathRequest.FlightLegs[2].Arrival", FieldLocation{Path: "flight_legs[2]"}, 2},
+		{"CalculateFlightPathRequest.FlightLegs[36].Arrival", FieldLocation{Path: "flight_legs[36][1]"}, 36},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			location, leg := request.locate(tt.namespace)
+			assert.Equal(t, location, tt.wantLocation)
+			assert.Equal(t, leg, tt.wantLeg)
+		})
+	}
+}
+
+func TestCalculateFlightPathRequest_LocateCSV(t *testing.T) {
+	request := &CalculateFlightPathRequest{csvRows: []int{2, 4}}
+
+	location, leg := request.locate("CalculateFlightPathRequest.FlightLegs[1].Arrival")
+	assert.Equal(t, location, FieldLocation{Row: 4, Column: "arrival"})
+	assert.Equal(t, leg, 1)
+
+	location, leg = request.locate("CalculateFlightPathRequest.FlightLegs")
+	assert.Equal(t, location, FieldLocation{})
+	assert.Equal(t, leg, -1)
+}
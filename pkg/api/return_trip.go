@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var ErrInvalidReturnTrip = errors.New(
+	"invalid return trip - return_trip must be a boolean, and stay a non-negative duration given with return_trip")
+
+// returnTripOptions tell whether the return trip of the flight path should be calculated instead, as told by the
+// return_trip and stay query parameters.
+type returnTripOptions struct {
+	enabled bool
+	// stay is how long the traveler stays at the destination before the return trip. The schedule is only mirrored
+	// if it is given.
+	stay *time.Duration
+}
+
+func parseReturnTripOptions(c *gin.Context) (returnTripOptions, error) {
+	var options returnTripOptions
+
+	if value, ok := c.GetQuery("return_trip"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return options, ErrInvalidReturnTrip
+		}
+		options.enabled = enabled
+	}
+
+	if value, ok := c.GetQuery("stay"); ok {
+		stay, err := time.ParseDuration(value)
+		if err != nil || stay < 0 || !options.enabled {
+			return options, ErrInvalidReturnTrip
+		}
+		options.stay = &stay
+	}
+
+	return options, nil
+}
+
+func (o returnTripOptions) apply(flightPath *model.FlightPath) (*model.FlightPath, error) {
+	switch {
+	case !o.enabled:
+		return flightPath, nil
+	case o.stay == nil:
+		return domain.ReturnTrip(flightPath), nil
+	default:
+		return domain.MirroredReturnTrip(flightPath, *o.stay)
+	}
+}
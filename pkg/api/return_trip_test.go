@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func postReturnTrip(router *gin.Engine, query, payload string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths?"+query, strings.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+const returnTripPayload = `{
+	"flight_legs": [
+		{"departure": "ATL", "arrival": "GSO",
+			"departure_time": "2024-03-25T18:00:00-04:00", "arrival_time": "2024-03-25T19:00:00-04:00"},
+		{"departure": "SFO", "arrival": "ATL",
+			"departure_time": "2024-03-25T08:00:00-07:00", "arrival_time": "2024-03-25T16:00:00-04:00"}
+	],
+	"flight_leg_format": "object"
+}`
+
+func TestCalculateFlightPath_ReturnTrip(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postReturnTrip(router, "return_trip=true", `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(),
+		`{"origin":"EWR","destination":"SFO","flight_legs":[["EWR","ATL"],["ATL","SFO"]]}`)
+}
+
+func TestCalculateFlightPath_ReturnTripDropsTimes(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postReturnTrip(router, "return_trip=true", returnTripPayload)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{"origin":"GSO","destination":"SFO","flight_legs":[
+		{"departure":"GSO","arrival":"ATL"},
+		{"departure":"ATL","arrival":"SFO"}
+	]}`)
+}
+
+func TestCalculateFlightPath_MirroredReturnTrip(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postReturnTrip(router, "return_trip=true&stay=48h", returnTripPayload)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{"origin":"GSO","destination":"SFO","flight_legs":[
+		{"departure":"GSO","arrival":"ATL",
+			"departure_time":"2024-03-27T19:00:00-04:00","arrival_time":"2024-03-27T20:00:00-04:00"},
+		{"departure":"ATL","arrival":"SFO",
+			"departure_time":"2024-03-27T22:00:00-04:00","arrival_time":"2024-03-28T00:00:00-07:00"}
+	]}`)
+}
+
+func TestCalculateFlightPath_MirroredReturnTripStreamed(t *testing.T) {
+	router := newTestRouter(t, streamingConfig())
+
+	response := postReturnTrip(router, "return_trip=1&stay=0s", returnTripPayload)
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(), `"departure_time":"2024-03-25T19:00:00-04:00"`)
+	assert.Contains(t, response.Body.String(), `"origin":"GSO"`)
+}
+
+func TestCalculateFlightPath_MirroredReturnTripWithoutTimes(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postReturnTrip(router, "return_trip=true&stay=72h", `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"missing_flight_leg_times"`)
+}
+
+func TestCalculateFlightPath_InvalidReturnTrip(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	for _, query := range []string{"return_trip=maybe", "return_trip=true&stay=3d", "return_trip=true&stay=-1h", "stay=1h",
+		"return_trip=false&stay=1h"} {
+		t.Run(query, func(t *testing.T) {
+			response := postReturnTrip(router, query, `{"flight_legs": [["ATL", "EWR"]]}`)
+
+			assert.Equal(t, response.Code, 400)
+			assert.Contains(t, response.Body.String(), `"code":"invalid_return_trip"`)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const (
+	defaultRouteStops = 2
+	// maxRouteStops keeps the search small, since the number of routes grows quickly with every stop
+	maxRouteStops = 4
+)
+
+var (
+	ErrInvalidRouteSearch = errors.New(
+		"invalid route search - from and to must be different airport codes, and max_stops a number from 0 to 4")
+	ErrRouteNetworkUnavailable = errors.New("route network unavailable - both ROUTES_FILE and AIRPORTS_FILE are needed")
+)
+
+type routeSearch struct {
+	from, to model.AirportCode
+	maxStops int
+}
+
+func parseRouteSearch(c *gin.Context) (routeSearch, error) {
+	search := routeSearch{
+		// airport codes are valid in any case, but the route network has them in upper case
+		from:     model.AirportCode(strings.ToUpper(c.Query("from"))),
+		to:       model.AirportCode(strings.ToUpper(c.Query("to"))),
+		maxStops: defaultRouteStops,
+	}
+	if !search.from.IsValid() || !search.to.IsValid() || search.from == search.to {
+		return search, ErrInvalidRouteSearch
+	}
+
+	if value, ok := c.GetQuery("max_stops"); ok {
+		maxStops, err := strconv.Atoi(value)
+		if err != nil || maxStops < 0 || maxStops > maxRouteStops {
+			return search, ErrInvalidRouteSearch
+		}
+		search.maxStops = maxStops
+	}
+
+	return search, nil
+}
+
+type RouteSearchResponse struct {
+	FewestStops      RouteResponse `json:"fewest_stops"`
+	ShortestDistance RouteResponse `json:"shortest_distance"`
+}
+
+type RouteResponse struct {
+	Stops int `json:"stops"`
+	// DistanceKm is the great-circle distance of the route, rounded to the kilometer
+	DistanceKm float64           `json:"distance_km"`
+	FlightPath *model.FlightPath `json:"flight_path"`
+}
+
+func newRouteResponse(route domain.FoundRoute) RouteResponse {
+	return RouteResponse{
+		Stops:      route.Stops(),
+		DistanceKm: math.Round(route.Distance),
+		FlightPath: route.FlightPath,
+	}
+}
+
+// FindRoutes searches the route network for the route between two airports with the fewest stops, and for the
+// shortest one, as told by the from, to and max_stops query parameters.
+func FindRoutes(c *gin.Context) {
+	search, err := parseRouteSearch(c)
+	if err != nil {
+		abortWithError(c, NewValidationError(err))
+		return
+	}
+	if routes == nil || airports == nil {
+		abortWithError(c, ErrRouteNetworkUnavailable)
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"From":     search.from,
+		"To":       search.to,
+		"MaxStops": search.maxStops,
+	}).Info("Searching for routes")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.CalculationTimeout)
+	defer cancel()
+
+	result, err := domain.NewRouteFinder(routes, airports).FindRoutes(ctx, search.from, search.to, search.maxStops)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(200, RouteSearchResponse{
+		FewestStops:      newRouteResponse(result.FewestStops),
+		ShortestDistance: newRouteResponse(result.ShortestDistance),
+	})
+}
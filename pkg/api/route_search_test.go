@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouteSearchTestRouter(t *testing.T, c Config) *gin.Engine {
+	router := newTestRouter(t, c)
+	router.GET("/routes", FindRoutes)
+	return router
+}
+
+func routeNetworkConfig() Config {
+	c := DefaultConfig()
+	c.RoutesFile = "../../data/routes.dat"
+	c.AirportsFile = "../../data/airports.dat"
+	return c
+}
+
+func getRoutes(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/routes?"+query, nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestFindRoutes(t *testing.T) {
+	router := newRouteSearchTestRouter(t, routeNetworkConfig())
+
+	response := getRoutes(router, "from=SFO&to=GSO&max_stops=2")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"fewest_stops": {
+			"stops": 1,
+			"distance_km": 3912,
+			"flight_path": {
+				"origin": "SFO",
+				"destination": "GSO",
+				"flight_legs": [["SFO", "ORD"], ["ORD", "GSO"]]
+			}
+		},
+		"shortest_distance": {
+			"stops": 1,
+			"distance_km": 3912,
+			"flight_path": {
+				"origin": "SFO",
+				"destination": "GSO",
+				"flight_legs": [["SFO", "ORD"], ["ORD", "GSO"]]
+			}
+		}
+	}`)
+}
+
+func TestFindRoutes_FewestStopsIsNotTheShortest(t *testing.T) {
+	router := newRouteSearchTestRouter(t, routeNetworkConfig())
+
+	response := getRoutes(router, "from=LAX&to=SEA")
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{
+		"fewest_stops": {
+			"stops": 1,
+			"distance_km": 6631,
+			"flight_path": {
+				"origin": "LAX",
+				"destination": "SEA",
+				"flight_legs": [["LAX", "ATL"], ["ATL", "SEA"]]
+			}
+		},
+		"shortest_distance": {
+			"stops": 2,
+			"distance_km": 4031,
+			"flight_path": {
+				"origin": "LAX",
+				"destination": "SEA",
+				"flight_legs": [["LAX", "DEN"], ["DEN", "SFO"], ["SFO", "SEA"]]
+			}
+		}
+	}`)
+}
+
+func TestFindRoutes_LowerCaseAirportCodes(t *testing.T) {
+	router := newRouteSearchTestRouter(t, routeNetworkConfig())
+
+	response := getRoutes(router, "from=sfo&to=gso")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(),
+		`"fewest_stops":{"stops":1,"distance_km":3912,"flight_path":{"origin":"SFO","destination":"GSO",`)
+}
+
+func TestFindRoutes_MaxStops(t *testing.T) {
+	router := newRouteSearchTestRouter(t, routeNetworkConfig())
+
+	response := getRoutes(router, "from=LAX&to=SEA&max_stops=1")
+
+	assert.Equal(t, response.Code, 200)
+	assert.Contains(t, response.Body.String(),
+		`"shortest_distance":{"stops":1,"distance_km":6631,"flight_path":{"origin":"LAX","destination":"SEA",`)
+}
+
+func TestFindRoutes_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		query      string
+		wantStatus int
+		wantCode   string
+	}{
+		{"missing airports", routeNetworkConfig(), "from=SFO", 400, CodeInvalidRouteSearch},
+		{"invalid airport code", routeNetworkConfig(), "from=SFO&to=G5O", 400, CodeInvalidRouteSearch},
+		{"same airports", routeNetworkConfig(), "from=SFO&to=SFO", 400, CodeInvalidRouteSearch},
+		{"same airports in another case", routeNetworkConfig(), "from=SFO&to=sfo", 400, CodeInvalidRouteSearch},
+		{"invalid max stops", routeNetworkConfig(), "from=SFO&to=GSO&max_stops=two", 400, CodeInvalidRouteSearch},
+		{"too many stops", routeNetworkConfig(), "from=SFO&to=GSO&max_stops=5", 400, CodeInvalidRouteSearch},
+		{"unknown airport", routeNetworkConfig(), "from=SFO&to=XYZ", 404, CodeUnknownAirport},
+		{"no route", routeNetworkConfig(), "from=SFO&to=GSO&max_stops=0", 404, CodeRouteNotFound},
+		{"no route network", DefaultConfig(), "from=SFO&to=GSO", 501, CodeRouteNetworkUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := newRouteSearchTestRouter(t, test.config)
+
+			response := getRoutes(router, test.query)
+
+			assert.Equal(t, response.Code, test.wantStatus)
+			assert.Contains(t, response.Body.String(), `"code":"`+test.wantCode+`"`)
+		})
+	}
+}
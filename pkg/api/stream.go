@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	goValidator "github.com/go-playground/validator/v10"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+	"github.com/felipead/flight-path-tracker/pkg/validator"
+)
+
+var errUnexpectedJSONToken = errors.New("unexpected JSON token")
+
+// flightPathStream decodes a JSON request body token by token, feeding each flight leg to a domain.FlightPathBuilder
+// as soon as it is decoded. The body is never held in memory as a whole: only the builder keeps the flight legs, which
+// takes much less memory than the decoded request would. That makes it suitable for itineraries with hundreds of
+// thousands of flight legs.
+//
+// It fails fast on the first flight leg that conflicts with the ones before it, without reading the rest of the body.
+// Otherwise, like CalculateFlightPathRequest.UnmarshalJSON, it goes through all flight legs, so that the errors of all
+// invalid ones are returned together. Once a flight leg is invalid, no more are fed to the builder, so conflicts
+// between the remaining ones are not looked for.
+type flightPathStream struct {
+	decoder *json.Decoder
+	builder *domain.FlightPathBuilder
+
+	// flightLegCount is the number of flight legs decoded so far, whether they are valid or not
+	flightLegCount int
+	// flightLegErrors are the errors of the invalid flight legs decoded so far
+	flightLegErrors fieldErrors
+
+	// flightLegFormat is how the flight legs of the response should be formatted
+	flightLegFormat model.FlightLegFormat
+}
+
+func newFlightPathStream(body io.Reader, options domain.CalculationOptions) *flightPathStream {
+	return &flightPathStream{
+		decoder: json.NewDecoder(body),
+		builder: domain.NewFlightPathBuilderWithOptions(options),
+	}
+}
+
+// decode reads the whole body. Decoding and validation errors are validation errors, while flight legs that
+// conflict with each other are returned as is.
+func (s *flightPathStream) decode(ctx context.Context) error {
+	if err := s.expectDelim('{'); err != nil {
+		return NewValidationError(err)
+	}
+
+	for s.decoder.More() {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return NewValidationError(err)
+		}
+
+		switch token {
+		case "flight_legs":
+			err = s.decodeFlightLegs(ctx)
+		case "flight_leg_format":
+			err = decodeValue(s.decoder, &s.flightLegFormat)
+		default:
+			// unknown properties are ignored, like json.Unmarshal does
+			var ignored json.RawMessage
+			err = decodeValue(s.decoder, &ignored)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.expectDelim('}'); err != nil {
+		return NewValidationError(err)
+	}
+
+	return s.validate()
+}
+
+func (s *flightPathStream) decodeFlightLegs(ctx context.Context) error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return NewValidationError(err)
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return NewValidationError(fmt.Errorf("%w %v - flight_legs must be an array", errUnexpectedJSONToken, token))
+	}
+
+	validate := validator.GetValidator()
+
+	for ; s.decoder.More(); s.flightLegCount++ {
+		i := s.flightLegCount
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("flight path decoding aborted; %w", err)
+		}
+
+		if i >= config.MaxFlightLegs {
+			return NewValidationError(tooManyFlightLegsError())
+		}
+
+		var rawLeg json.RawMessage
+		if err := decodeValue(s.decoder, &rawLeg); err != nil {
+			return err
+		}
+
+		leg, format, err := model.UnmarshalFlightLeg(rawLeg, config.StrictFlightLegFields)
+		if err != nil {
+			s.flightLegErrors = append(s.flightLegErrors, newFlightLegFieldError(i, err))
+			continue
+		}
+
+		if err := validate.Struct(&leg); err != nil {
+			var validationErrors goValidator.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				return err
+			}
+			errs := newValidationFieldErrors(validationErrors, locateStreamedFlightLeg(i, format))
+			s.flightLegErrors = append(s.flightLegErrors, errs...)
+			continue
+		}
+
+		if len(s.flightLegErrors) > 0 {
+			continue
+		}
+		if err := s.builder.AddFlightLeg(leg); err != nil {
+			return err
+		}
+	}
+
+	if err := s.expectDelim(']'); err != nil {
+		return NewValidationError(err)
+	}
+	return nil
+}
+
+// validate checks the rest of the request once all flight legs have been decoded, and reports its errors along with
+// the ones of the flight legs.
+func (s *flightPathStream) validate() error {
+	request := CalculateFlightPathRequest{FlightLegFormat: s.flightLegFormat}
+
+	fields := []string{"FlightLegFormat"}
+	if s.flightLegCount == 0 {
+		fields = append(fields, "FlightLegs")
+	}
+
+	var requestErrors fieldErrors
+	if err := validator.GetValidator().StructPartial(&request, fields...); err != nil {
+		var errs goValidator.ValidationErrors
+		if !errors.As(err, &errs) {
+			return err
+		}
+		requestErrors = newValidationFieldErrors(errs, request.locate)
+	}
+
+	if errs := mergeFieldErrors(s.flightLegErrors, requestErrors); len(errs) > 0 {
+		return NewValidationError(errs)
+	}
+	return nil
+}
+
+func (s *flightPathStream) expectDelim(delim json.Delim) error {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("%w %v - expected %v", errUnexpectedJSONToken, token, delim)
+	}
+	return nil
+}
+
+func decodeValue(decoder *json.Decoder, value interface{}) error {
+	if err := decoder.Decode(value); err != nil {
+		return NewValidationError(err)
+	}
+	return nil
+}
+
+// locateStreamedFlightLeg locates the fields of a FlightLeg validated on its own, whose namespace is like
+// "FlightLeg.Arrival", as if it had been validated as part of the request.
+func locateStreamedFlightLeg(index int, format model.FlightLegFormat) func(string) (FieldLocation, int) {
+	return func(namespace string) (FieldLocation, int) {
+		field := strings.ToLower(strings.TrimPrefix(namespace, "FlightLeg."))
+		return FieldLocation{Path: flightLegFieldPath(index, format, field)}, index
+	}
+}
+
+func tooManyFlightLegsError() error {
+	return fmt.Errorf("%w - the maximum is %v", ErrTooManyFlightLegs, config.MaxFlightLegs)
+}
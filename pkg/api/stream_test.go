@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// streamingConfig makes every JSON request body to be decoded as a stream
+func streamingConfig() Config {
+	c := DefaultConfig()
+	c.StreamingThreshold = 1
+	return c
+}
+
+// postFlightPathsUnknownLength sends a body without a Content-Length, like a chunked upload
+func postFlightPathsUnknownLength(router *gin.Engine, payload string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/flight_paths", io.MultiReader(strings.NewReader(payload)))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCalculateFlightPath_Streamed(t *testing.T) {
+	router := newTestRouter(t, streamingConfig())
+
+	response := postFlightPaths(router, `{
+		"comment": {"ignored": [1, 2, 3]},
+		"flight_legs": [
+			{"departure": "ATL", "arrival": "EWR", "arrival_time": "2024-03-25T18:40:00Z"},
+			"SFO-ATL",
+			["GSO", "SFO"]
+		],
+		"flight_leg_format": "object"
+	}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(), `{"origin":"GSO","destination":"EWR","flight_legs":[
+		{"departure":"GSO","arrival":"SFO"},
+		{"departure":"SFO","arrival":"ATL"},
+		{"departure":"ATL","arrival":"EWR","arrival_time":"2024-03-25T18:40:00Z"}
+	]}`)
+}
+
+func TestCalculateFlightPath_StreamedUnknownLength(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	response := postFlightPathsUnknownLength(router, `{"flight_legs": [["ATL", "EWR"], ["SFO", "ATL"]]}`)
+
+	assert.Equal(t, response.Code, 200)
+	assert.JSONEq(t, response.Body.String(),
+		`{"origin":"SFO","destination":"EWR","flight_legs":[["SFO","ATL"],["ATL","EWR"]]}`)
+}
+
+func TestCalculateFlightPath_StreamedLongItinerary(t *testing.T) {
+	router := newTestRouter(t, DefaultConfig())
+
+	const length = generator.PlainAirportCodes - 1
+	chain := generator.New(42).Chain(length)
+	legs := slices.Clone(chain)
+	generator.New(42).Shuffle(legs)
+
+	body, err := generator.RequestBody(legs, model.FlightLegFormatString)
+	assert.NoError(t, err)
+
+	response := postFlightPathsUnknownLength(router, string(body))
+
+	assert.Equal(t, response.Code, 200)
+
+	var flightPath model.FlightPath
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &flightPath))
+	assert.Equal(t, flightPath.Origin, chain[0].Departure)
+	assert.Equal(t, flightPath.Destination, chain[length-1].Arrival)
+	assert.Equal(t, flightPath.FlightLegs, chain)
+}
+
+func TestCalculateFlightPath_StreamedFailsFast(t *testing.T) {
+	router := newTestRouter(t, streamingConfig())
+
+	// the buffered decoding would have reported the invalid flight legs after the conflict instead
+	response := postFlightPaths(router, `{"flight_legs": [["SFO", "ATL"], ["SFO", "EWR"], ["ABO"], ["A", "B"]]}`)
+
+	assert.Equal(t, response.Code, 400)
+	assert.Contains(t, response.Body.String(), `"code":"multiple_outbound_legs"`)
+}
+
+func TestCalculateFlightPath_StreamedReportsLikeBuffered(t *testing.T) {
+	payloads := map[string]string{
+		"invalid flight legs":            `{"flight_legs": [["SFO", "ATL"], ["ABO"], ["A", "B"]]}`,
+		"invalid flight legs and format": `{"flight_legs": [["SFO", "ATL"], "ATL"], "flight_leg_format": "csv"}`,
+		"conflict":                       `{"flight_legs": [["SFO", "ATL"], ["GSO", "ATL"]]}`,
+	}
+
+	buffered := newTestRouter(t, DefaultConfig())
+	streamed := newTestRouter(t, streamingConfig())
+
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			want := postFlightPaths(buffered, payload)
+			response := postFlightPaths(streamed, payload)
+
+			assert.Equal(t, response.Code, want.Code)
+
+			var body, wantBody ErrorResponse
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+			assert.NoError(t, json.Unmarshal(want.Body.Bytes(), &wantBody))
+			body.CorrelationID, wantBody.CorrelationID = "", ""
+			assert.Equal(t, body, wantBody)
+		})
+	}
+}
+
+func TestCalculateFlightPath_StreamedValidationErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    string
+		wantCode   string
+		wantParams []FieldError
+	}{
+		{
+			name:     "invalid flight leg",
+			payload:  `{"flight_legs": [["SFO", "ATL"], ["ABO"], ["A", "B"]]}`,
+			wantCode: "invalid_flight_leg",
+			wantParams: []FieldError{{
+				FieldLocation: FieldLocation{Path: "flight_legs[1]"},
+				Code:          "invalid_flight_leg",
+				Value:         []interface{}{"ABO"},
+				Message: "Each flight leg must be a list with the departure and arrival airport codes, such as " +
+					`["SFO", "ORD"]; an object, such as {"departure": "SFO", "arrival": "ORD"}; or a string, such as "SFO-ORD".`,
+			}, {
+				FieldLocation: FieldLocation{Path: "flight_legs[2][0]"},
+				Code:          "invalid_airport_code",
+				Value:         "A",
+				Message:       "departure must be a 3-letter IATA airport code",
+			}, {
+				FieldLocation: FieldLocation{Path: "flight_legs[2][1]"},
+				Code:          "invalid_airport_code",
+				Value:         "B",
+				Message:       "arrival must be a 3-letter IATA airport code",
+			}},
+		},
+		{
+			name:     "invalid airport code",
+			payload:  `{"flight_legs": [["SFO", "ATL"], {"departure": "ATL", "arrival": "E5R"}]}`,
+			wantCode: "invalid_airport_code",
+			wantParams: []FieldError{{
+				FieldLocation: FieldLocation{Path: "flight_legs[1].arrival"},
+				Code:          "invalid_airport_code",
+				Value:         "E5R",
+				Message:       "arrival must be a 3-letter IATA airport code",
+			}},
+		},
+		{
+			name:     "missing flight legs",
+			payload:  `{"flight_legs": []}`,
+			wantCode: "missing_flight_legs",
+			wantParams: []FieldError{{
+				FieldLocation: FieldLocation{Path: "flight_legs"},
+				Code:          "missing_flight_legs",
+				Message:       "flight_legs is a required field",
+			}},
+		},
+		{
+			name:     "invalid flight leg format",
+			payload:  `{"flight_legs": [["SFO", "ATL"]], "flight_leg_format": "csv"}`,
+			wantCode: "invalid_flight_leg_format",
+			wantParams: []FieldError{{
+				FieldLocation: FieldLocation{Path: "flight_leg_format"},
+				Code:          "invalid_flight_leg_format",
+				Value:         "csv",
+				Message:       "flight_leg_format must be one of [array object string]",
+			}},
+		},
+		{
+			name:     "flight legs are not an array",
+			payload:  `{"flight_legs": {"departure": "SFO", "arrival": "ATL"}}`,
+			wantCode: "malformed_request",
+		},
+		{
+			name:     "body is not an object",
+			payload:  `[["SFO", "ATL"]]`,
+			wantCode: "malformed_request",
+		},
+		{
+			name:     "truncated body",
+			payload:  `{"flight_legs": [["SFO", "ATL"]`,
+			wantCode: "malformed_request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, streamingConfig())
+
+			response := postFlightPaths(router, tt.payload)
+
+			assert.Equal(t, response.Code, 400)
+
+			var body ErrorResponse
+			assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+			assert.Equal(t, body.Code, tt.wantCode)
+			assert.Equal(t, body.InvalidParams, tt.wantParams)
+		})
+	}
+}
+
+func TestCalculateFlightPath_TooManyFlightLegs(t *testing.T) {
+	for name, c := range map[string]Config{"buffered": DefaultConfig(), "streamed": streamingConfig()} {
+		t.Run(name, func(t *testing.T) {
+			c.MaxFlightLegs = 2
+			router := newTestRouter(t, c)
+
+			response := postFlightPaths(router, `{"flight_legs": [["SFO", "ATL"], ["ATL", "GSO"], ["GSO", "IND"]]}`)
+
+			assert.Equal(t, response.Code, 413)
+			assert.Contains(t, response.Body.String(), `"code":"too_many_flight_legs"`)
+		})
+	}
+}
+
+func TestCalculateFlightPath_RequestTooLarge(t *testing.T) {
+	for name, c := range map[string]Config{"buffered": DefaultConfig(), "streamed": streamingConfig()} {
+		t.Run(name, func(t *testing.T) {
+			c.MaxRequestSize = 32
+			router := newTestRouter(t, c)
+
+			response := postFlightPaths(router, `{"flight_legs": [["SFO", "ATL"], ["ATL", "GSO"], ["GSO", "IND"]]}`)
+
+			assert.Equal(t, response.Code, 413)
+			assert.Contains(t, response.Body.String(), `"code":"request_too_large"`)
+		})
+	}
+}
+
+func TestFlightPathStream_Canceled(t *testing.T) {
+	assert.NoError(t, Init(DefaultConfig()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stream := newFlightPathStream(strings.NewReader(`{"flight_legs": [["SFO", "ATL"]]}`), domain.CalculationOptions{})
+	err := stream.decode(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, ErrorCode(err), CodeRequestCanceled)
+}
+
+func TestFlightPathStream_ConflictIsNotValidationError(t *testing.T) {
+	assert.NoError(t, Init(DefaultConfig()))
+
+	body := strings.NewReader(`{"flight_legs": [["SFO", "ATL"], ["GSO", "ATL"]]}`)
+	stream := newFlightPathStream(body, domain.CalculationOptions{})
+	err := stream.decode(context.Background())
+
+	assert.ErrorIs(t, err, domain.ErrInboundConnectionExists)
+	assert.Equal(t, ClassifyError(err), ErrorClassDomainConflict)
+	assert.Equal(t, stream.builder.Length(), 1)
+}
+
+func TestFlightPathStream_StopsReadingAtConflict(t *testing.T) {
+	assert.NoError(t, Init(DefaultConfig()))
+
+	// the rest of the body fails to be read, so the conflict is only reported if it is never reached
+	body := io.MultiReader(
+		strings.NewReader(`{"flight_legs": [["SFO", "ATL"], ["GSO", "ATL"], `),
+		iotest.ErrReader(errors.New("read past the conflict")),
+	)
+	stream := newFlightPathStream(body, domain.CalculationOptions{})
+	err := stream.decode(context.Background())
+
+	assert.ErrorIs(t, err, domain.ErrInboundConnectionExists)
+}
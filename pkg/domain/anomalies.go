@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	ErrImplausibleSpeed      = errors.New("implausible speed")
+	ErrOverlappingFlightLegs = errors.New("overlapping flight legs")
+	ErrShortTurnaround       = errors.New("short turnaround")
+)
+
+// AnomalyThresholds tell when the times of a flight path are anomalous.
+type AnomalyThresholds struct {
+	// SuspiciousSpeed and ImpossibleSpeed are the average speeds, in kilometers per hour, above which a flight leg is
+	// a medium or high severity anomaly. The speed is the great-circle distance over the time between departure and
+	// arrival, so it can only be calculated when the coordinates of both airports are known.
+	SuspiciousSpeed float64
+	ImpossibleSpeed float64
+	// ShortTurnaround and VeryShortTurnaround are the times between arriving at an airport and departing from it
+	// below which a connection is a low or medium severity anomaly.
+	ShortTurnaround     time.Duration
+	VeryShortTurnaround time.Duration
+}
+
+// DefaultAnomalyThresholds are such that airliners, which cruise at about 900 km/h, rarely go beyond the suspicious
+// speed even with strong tailwinds, and never beyond the impossible one. Turnarounds are measured against the
+// shortest connection times of large airports.
+var DefaultAnomalyThresholds = AnomalyThresholds{
+	SuspiciousSpeed:     1000,
+	ImpossibleSpeed:     1200,
+	ShortTurnaround:     30 * time.Minute,
+	VeryShortTurnaround: 10 * time.Minute,
+}
+
+// DetectAnomalies checks the times of the sorted flight legs of a flight path for signs that they could not have been
+// flown by a single traveler:
+//
+//   - A flight leg that arrives before it departs, or that would have been flown faster than the thresholds allow.
+//   - A flight leg that departs before the one before it arrives, so the traveler would be in two places at once.
+//   - A connection shorter than the thresholds allow.
+//
+// Flight legs without times are not checked, and neither are the speeds of flight legs whose airports the locator
+// does not know. The locator may be nil.
+func DetectAnomalies(
+	flightLegs []model.FlightLeg, locator AirportLocator, thresholds AnomalyThresholds,
+) []model.Anomaly {
+	var anomalies []model.Anomaly
+
+	for i, leg := range flightLegs {
+		if anomaly, ok := detectImplausibleSpeed(leg, locator, thresholds); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+
+		if i > 0 {
+			if anomaly, ok := detectConnectionAnomaly(flightLegs[i-1], leg, thresholds); ok {
+				anomalies = append(anomalies, anomaly)
+			}
+		}
+	}
+
+	return anomalies
+}
+
+func detectImplausibleSpeed(
+	leg model.FlightLeg, locator AirportLocator, thresholds AnomalyThresholds,
+) (model.Anomaly, bool) {
+	if leg.DepartureTime == nil || leg.ArrivalTime == nil {
+		return model.Anomaly{}, false
+	}
+
+	anomaly := model.Anomaly{FlightLegs: []model.FlightLeg{leg}}
+
+	duration := leg.ArrivalTime.Sub(*leg.DepartureTime)
+	if duration <= 0 {
+		anomaly.Severity = model.AnomalySeverityHigh
+		anomaly.Err = fmt.Errorf("%w; the flight leg from %v to %v does not arrive after it departs",
+			ErrImplausibleSpeed, leg.Departure, leg.Arrival)
+		return anomaly, true
+	}
+
+	if locator == nil {
+		return model.Anomaly{}, false
+	}
+	departure, ok := locator.Coordinates(leg.Departure)
+	if !ok {
+		return model.Anomaly{}, false
+	}
+	arrival, ok := locator.Coordinates(leg.Arrival)
+	if !ok {
+		return model.Anomaly{}, false
+	}
+
+	speed := departure.DistanceTo(arrival) / duration.Hours()
+	switch {
+	case speed > thresholds.ImpossibleSpeed:
+		anomaly.Severity = model.AnomalySeverityHigh
+	case speed > thresholds.SuspiciousSpeed:
+		anomaly.Severity = model.AnomalySeverityMedium
+	default:
+		return model.Anomaly{}, false
+	}
+
+	anomaly.Err = fmt.Errorf("%w of %.0f km/h from %v to %v", ErrImplausibleSpeed, speed, leg.Departure, leg.Arrival)
+	return anomaly, true
+}
+
+// detectConnectionAnomaly checks the connection between two consecutive flight legs, at the airport where the first
+// one arrives and the second one departs.
+func detectConnectionAnomaly(before, after model.FlightLeg, thresholds AnomalyThresholds) (model.Anomaly, bool) {
+	if before.ArrivalTime == nil || after.DepartureTime == nil {
+		return model.Anomaly{}, false
+	}
+
+	anomaly := model.Anomaly{FlightLegs: []model.FlightLeg{before, after}}
+
+	turnaround := after.DepartureTime.Sub(*before.ArrivalTime)
+	switch {
+	case turnaround < 0:
+		anomaly.Severity = model.AnomalySeverityHigh
+		anomaly.Err = fmt.Errorf("%w; the flight leg from %v to %v departs %v before the one from %v arrives",
+			ErrOverlappingFlightLegs, after.Departure, after.Arrival, -turnaround, before.Departure)
+		return anomaly, true
+	case turnaround < thresholds.VeryShortTurnaround:
+		anomaly.Severity = model.AnomalySeverityMedium
+	case turnaround < thresholds.ShortTurnaround:
+		anomaly.Severity = model.AnomalySeverityLow
+	default:
+		return model.Anomaly{}, false
+	}
+
+	anomaly.Err = fmt.Errorf("%w of %v at %v", ErrShortTurnaround, turnaround, after.Departure)
+	return anomaly, true
+}
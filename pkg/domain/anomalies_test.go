@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// scheduledLeg is a flight leg that departs and arrives at the given times, in minutes after 08:00
+func scheduledLeg(departure, arrival model.AirportCode, departsAt, arrivesAt int) model.FlightLeg {
+	start := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	departureTime := start.Add(time.Duration(departsAt) * time.Minute)
+	arrivalTime := start.Add(time.Duration(arrivesAt) * time.Minute)
+	return model.FlightLeg{
+		Departure: departure, Arrival: arrival, DepartureTime: &departureTime, ArrivalTime: &arrivalTime,
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	// SFO to DEN is 1553 km, so flying it in an hour is impossible, and in 85 minutes suspicious
+	tests := []struct {
+		name       string
+		flightLegs []model.FlightLeg
+		want       []model.Anomaly
+		wantErrors []string
+	}{
+		{
+			name: "plausible schedule",
+			flightLegs: []model.FlightLeg{
+				scheduledLeg("SFO", "DEN", 0, 150),
+				scheduledLeg("DEN", "ORD", 210, 360),
+			},
+		},
+		{
+			name:       "without times",
+			flightLegs: []model.FlightLeg{{Departure: "SFO", Arrival: "DEN"}, {Departure: "DEN", Arrival: "ORD"}},
+		},
+		{
+			name:       "unknown airports",
+			flightLegs: []model.FlightLeg{scheduledLeg("SFO", "XXX", 0, 10)},
+		},
+		{
+			name:       "impossible speed",
+			flightLegs: []model.FlightLeg{scheduledLeg("SFO", "DEN", 0, 60)},
+			want: []model.Anomaly{
+				{Severity: model.AnomalySeverityHigh, FlightLegs: []model.FlightLeg{scheduledLeg("SFO", "DEN", 0, 60)}},
+			},
+			wantErrors: []string{"implausible speed of 1553 km/h from SFO to DEN"},
+		},
+		{
+			name:       "suspicious speed",
+			flightLegs: []model.FlightLeg{scheduledLeg("SFO", "DEN", 0, 85)},
+			want: []model.Anomaly{
+				{Severity: model.AnomalySeverityMedium, FlightLegs: []model.FlightLeg{scheduledLeg("SFO", "DEN", 0, 85)}},
+			},
+			wantErrors: []string{"implausible speed of 1096 km/h from SFO to DEN"},
+		},
+		{
+			name:       "arrives before it departs",
+			flightLegs: []model.FlightLeg{scheduledLeg("SFO", "XXX", 60, 0)},
+			want: []model.Anomaly{
+				{Severity: model.AnomalySeverityHigh, FlightLegs: []model.FlightLeg{scheduledLeg("SFO", "XXX", 60, 0)}},
+			},
+			wantErrors: []string{
+				"implausible speed; the flight leg from SFO to XXX does not arrive after it departs",
+			},
+		},
+		{
+			name: "overlapping flight legs",
+			flightLegs: []model.FlightLeg{
+				scheduledLeg("SFO", "DEN", 0, 150),
+				scheduledLeg("DEN", "ORD", 120, 270),
+			},
+			want: []model.Anomaly{{
+				Severity: model.AnomalySeverityHigh,
+				FlightLegs: []model.FlightLeg{
+					scheduledLeg("SFO", "DEN", 0, 150),
+					scheduledLeg("DEN", "ORD", 120, 270),
+				},
+			}},
+			wantErrors: []string{
+				"overlapping flight legs; the flight leg from DEN to ORD departs 30m0s before the one from SFO arrives",
+			},
+		},
+		{
+			name: "short and very short turnarounds",
+			flightLegs: []model.FlightLeg{
+				scheduledLeg("SFO", "DEN", 0, 150),
+				scheduledLeg("DEN", "ORD", 170, 320),
+				scheduledLeg("ORD", "GSO", 325, 425),
+			},
+			want: []model.Anomaly{
+				{
+					Severity: model.AnomalySeverityLow,
+					FlightLegs: []model.FlightLeg{
+						scheduledLeg("SFO", "DEN", 0, 150),
+						scheduledLeg("DEN", "ORD", 170, 320),
+					},
+				},
+				{
+					Severity: model.AnomalySeverityMedium,
+					FlightLegs: []model.FlightLeg{
+						scheduledLeg("DEN", "ORD", 170, 320),
+						scheduledLeg("ORD", "GSO", 325, 425),
+					},
+				},
+			},
+			wantErrors: []string{"short turnaround of 20m0s at DEN", "short turnaround of 5m0s at ORD"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := DetectAnomalies(test.flightLegs, testAirports, DefaultAnomalyThresholds)
+
+			var gotErrors []string
+			for i := range got {
+				gotErrors = append(gotErrors, got[i].Err.Error())
+				got[i].Err = nil
+			}
+			assert.Equal(t, got, test.want)
+			assert.Equal(t, gotErrors, test.wantErrors)
+		})
+	}
+}
+
+func TestDetectAnomalies_WithoutLocator(t *testing.T) {
+	flightLegs := []model.FlightLeg{scheduledLeg("SFO", "DEN", 0, 60), scheduledLeg("DEN", "ORD", 50, 200)}
+
+	got := DetectAnomalies(flightLegs, nil, DefaultAnomalyThresholds)
+
+	assert.Len(t, got, 1)
+	assert.ErrorIs(t, got[0].Err, ErrOverlappingFlightLegs)
+}
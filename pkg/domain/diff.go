@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// DiffFlightPaths finds what changed from one flight path to another, both sorted as by CalculateFlightPath. Flight
+// legs are the same if they have the same departure and arrival airports, as well as the same departure and arrival
+// times, so a flight leg that was rescheduled is both removed and added.
+func DiffFlightPaths(before, after *model.FlightPath) *model.FlightPathDiff {
+	// Since there are no branches in a flight path, there's at most one flight leg leaving each airport.
+	beforeByDeparture := make(map[model.AirportCode]model.FlightLeg, len(before.FlightLegs))
+	for _, leg := range before.FlightLegs {
+		beforeByDeparture[leg.Departure] = leg
+	}
+	afterByDeparture := make(map[model.AirportCode]model.FlightLeg, len(after.FlightLegs))
+	for _, leg := range after.FlightLegs {
+		afterByDeparture[leg.Departure] = leg
+	}
+
+	beforeConnections := connectionsOf(before)
+	afterConnections := connectionsOf(after)
+
+	return &model.FlightPathDiff{
+		Before:               before,
+		After:                after,
+		OriginPreserved:      before.Origin == after.Origin,
+		DestinationPreserved: before.Destination == after.Destination,
+		AddedFlightLegs:      flightLegsMissingFrom(after.FlightLegs, beforeByDeparture),
+		RemovedFlightLegs:    flightLegsMissingFrom(before.FlightLegs, afterByDeparture),
+		AddedConnections:     airportsMissingFrom(afterConnections, beforeConnections),
+		RemovedConnections:   airportsMissingFrom(beforeConnections, afterConnections),
+	}
+}
+
+// connectionsOf are the airports where the traveler changes flights: every airport but the origin and the destination
+func connectionsOf(flightPath *model.FlightPath) []model.AirportCode {
+	if len(flightPath.FlightLegs) == 0 {
+		return nil
+	}
+
+	connections := make([]model.AirportCode, 0, len(flightPath.FlightLegs)-1)
+	for _, leg := range flightPath.FlightLegs[1:] {
+		connections = append(connections, leg.Departure)
+	}
+	return connections
+}
+
+func flightLegsMissingFrom(legs []model.FlightLeg, others map[model.AirportCode]model.FlightLeg) []model.FlightLeg {
+	missing := make([]model.FlightLeg, 0)
+	for _, leg := range legs {
+		other, ok := others[leg.Departure]
+		if !ok || !sameFlightLeg(leg, other) {
+			missing = append(missing, leg)
+		}
+	}
+	return missing
+}
+
+func airportsMissingFrom(airports, others []model.AirportCode) []model.AirportCode {
+	otherSet := make(map[model.AirportCode]bool, len(others))
+	for _, airport := range others {
+		otherSet[airport] = true
+	}
+
+	missing := make([]model.AirportCode, 0)
+	for _, airport := range airports {
+		if !otherSet[airport] {
+			missing = append(missing, airport)
+		}
+	}
+	return missing
+}
+
+func sameFlightLeg(a, b model.FlightLeg) bool {
+	return a.Departure == b.Departure && a.Arrival == b.Arrival &&
+		sameTime(a.DepartureTime, b.DepartureTime) && sameTime(a.ArrivalTime, b.ArrivalTime)
+}
+
+// sameTime tells if both times are missing, or if both are the same instant, even in different time zones
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
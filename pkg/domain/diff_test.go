@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestDiffFlightPaths(t *testing.T) {
+	before := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "EWR",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "GSO"},
+			{Departure: "GSO", Arrival: "IND"},
+			{Departure: "IND", Arrival: "EWR"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		after *model.FlightPath
+		want  *model.FlightPathDiff
+	}{
+		{
+			name:  "same flight path",
+			after: before,
+			want: &model.FlightPathDiff{
+				OriginPreserved:      true,
+				DestinationPreserved: true,
+				AddedFlightLegs:      []model.FlightLeg{},
+				RemovedFlightLegs:    []model.FlightLeg{},
+				AddedConnections:     []model.AirportCode{},
+				RemovedConnections:   []model.AirportCode{},
+			},
+		},
+		{
+			name: "rerouted through another connection",
+			after: &model.FlightPath{
+				Origin:      "SFO",
+				Destination: "EWR",
+				FlightLegs: []model.FlightLeg{
+					{Departure: "SFO", Arrival: "ATL"},
+					{Departure: "ATL", Arrival: "ORD"},
+					{Departure: "ORD", Arrival: "IND"},
+					{Departure: "IND", Arrival: "EWR"},
+				},
+			},
+			want: &model.FlightPathDiff{
+				OriginPreserved:      true,
+				DestinationPreserved: true,
+				AddedFlightLegs: []model.FlightLeg{
+					{Departure: "ATL", Arrival: "ORD"},
+					{Departure: "ORD", Arrival: "IND"},
+				},
+				RemovedFlightLegs: []model.FlightLeg{
+					{Departure: "ATL", Arrival: "GSO"},
+					{Departure: "GSO", Arrival: "IND"},
+				},
+				AddedConnections:   []model.AirportCode{"ORD"},
+				RemovedConnections: []model.AirportCode{"GSO"},
+			},
+		},
+		{
+			name: "direct flight to another destination",
+			after: &model.FlightPath{
+				Origin:      "SFO",
+				Destination: "JFK",
+				FlightLegs:  []model.FlightLeg{{Departure: "SFO", Arrival: "JFK"}},
+			},
+			want: &model.FlightPathDiff{
+				OriginPreserved:      true,
+				DestinationPreserved: false,
+				AddedFlightLegs:      []model.FlightLeg{{Departure: "SFO", Arrival: "JFK"}},
+				RemovedFlightLegs:    before.FlightLegs,
+				AddedConnections:     []model.AirportCode{},
+				RemovedConnections:   []model.AirportCode{"ATL", "GSO", "IND"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.want.Before = before
+			test.want.After = test.after
+
+			assert.Equal(t, DiffFlightPaths(before, test.after), test.want)
+		})
+	}
+}
+
+func TestDiffFlightPaths_RescheduledFlightLeg(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	sameDeparture := departure.In(time.FixedZone("PDT", -7*60*60))
+	laterDeparture := departure.Add(2 * time.Hour)
+
+	before := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+			{Departure: "ATL", Arrival: "GSO", DepartureTime: &departure},
+		},
+	}
+	after := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &sameDeparture},
+			{Departure: "ATL", Arrival: "GSO", DepartureTime: &laterDeparture},
+		},
+	}
+
+	diff := DiffFlightPaths(before, after)
+
+	assert.Equal(t, diff.AddedFlightLegs, after.FlightLegs[1:])
+	assert.Equal(t, diff.RemovedFlightLegs, before.FlightLegs[1:])
+	assert.Empty(t, diff.AddedConnections)
+	assert.Empty(t, diff.RemovedConnections)
+}
@@ -0,0 +1,501 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+var ErrCycle = errors.New("the digraph has a cycle")
+
+// Digraph is a directed graph, where each edge, or connection, goes from one vertex, or point, to another. There can
+// be any number of connections between the same two points, and a point can be connected to itself.
+//
+// Points are kept in the order they were first added, so that every traversal below is deterministic. Whether a
+// point is in the digraph is tracked explicitly, so any value of T can be a point, including its zero value.
+type Digraph[T comparable] struct {
+	points []T
+	// indexOf is the position of each point in points. Connections refer to points by their position.
+	indexOf map[T]int
+	// outbound and inbound have the positions of the points each point is connected to, once for each connection
+	outbound    [][]int
+	inbound     [][]int
+	connections int
+
+	// removed marks the positions of the points that were removed. They are skipped until there are so many of them
+	// that the positions are compacted.
+	removed  []bool
+	removals int
+}
+
+func NewDigraph[T comparable]() *Digraph[T] {
+	return &Digraph[T]{
+		indexOf: make(map[T]int),
+	}
+}
+
+// AddPoint adds a point without any connections, unless the digraph already has it.
+func (g *Digraph[T]) AddPoint(a T) {
+	g.addPoint(a)
+}
+
+func (g *Digraph[T]) addPoint(a T) int {
+	if i, ok := g.indexOf[a]; ok {
+		return i
+	}
+
+	i := len(g.points)
+	g.points = append(g.points, a)
+	g.indexOf[a] = i
+	g.outbound = append(g.outbound, nil)
+	g.inbound = append(g.inbound, nil)
+	g.removed = append(g.removed, false)
+	return i
+}
+
+// positions walks the position and the value of each point that was not removed, in the order they were added
+func (g *Digraph[T]) positions() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, a := range g.points {
+			if !g.removed[i] && !yield(i, a) {
+				return
+			}
+		}
+	}
+}
+
+// AddConnection connects "from" to "to", adding the points if needed. Connecting the same points again adds another
+// connection between them.
+func (g *Digraph[T]) AddConnection(from, to T) {
+	i := g.addPoint(from)
+	j := g.addPoint(to)
+
+	g.outbound[i] = append(g.outbound[i], j)
+	g.inbound[j] = append(g.inbound[j], i)
+	g.connections++
+}
+
+// RemoveConnection removes one connection from "from" to "to", the last one added. It returns false if there's none.
+// The points are kept, even if they are left without connections.
+func (g *Digraph[T]) RemoveConnection(from, to T) bool {
+	i, ok := g.indexOf[from]
+	if !ok {
+		return false
+	}
+	j, ok := g.indexOf[to]
+	if !ok {
+		return false
+	}
+
+	if !slices.Contains(g.outbound[i], j) {
+		return false
+	}
+	g.outbound[i] = deleteLast(g.outbound[i], j)
+	g.inbound[j] = deleteLast(g.inbound[j], i)
+	g.connections--
+	return true
+}
+
+// RemovePoint removes a point, along with all of its connections. It returns false if the digraph doesn't have it.
+func (g *Digraph[T]) RemovePoint(a T) bool {
+	i, ok := g.indexOf[a]
+	if !ok {
+		return false
+	}
+
+	for _, j := range g.outbound[i] {
+		if j != i {
+			g.inbound[j] = deleteLast(g.inbound[j], i)
+		}
+	}
+	for _, j := range g.inbound[i] {
+		if j != i {
+			g.outbound[j] = deleteLast(g.outbound[j], i)
+		}
+	}
+	g.connections -= len(g.outbound[i]) + len(g.inbound[i])
+	// a connection to itself was counted twice
+	for _, j := range g.outbound[i] {
+		if j == i {
+			g.connections++
+		}
+	}
+
+	var nullValue T
+	g.points[i] = nullValue
+	g.outbound[i] = nil
+	g.inbound[i] = nil
+	g.removed[i] = true
+	g.removals++
+	delete(g.indexOf, a)
+
+	if g.removals > len(g.points)/2 {
+		g.compact()
+	}
+	return true
+}
+
+// deleteLast deletes the last occurrence of the position j
+func deleteLast(positions []int, j int) []int {
+	for k := len(positions) - 1; k >= 0; k-- {
+		if positions[k] == j {
+			return slices.Delete(positions, k, k+1)
+		}
+	}
+	return positions
+}
+
+// compact moves the points that were not removed to new positions, without gaps, keeping their order
+func (g *Digraph[T]) compact() {
+	positionOf := make([]int, len(g.points))
+	order := 0
+	for i := range g.points {
+		if !g.removed[i] {
+			positionOf[i] = order
+			order++
+		}
+	}
+
+	points := make([]T, 0, order)
+	outbound := make([][]int, 0, order)
+	inbound := make([][]int, 0, order)
+	for i, a := range g.positions() {
+		for k, j := range g.outbound[i] {
+			g.outbound[i][k] = positionOf[j]
+		}
+		for k, j := range g.inbound[i] {
+			g.inbound[i][k] = positionOf[j]
+		}
+		points = append(points, a)
+		outbound = append(outbound, g.outbound[i])
+		inbound = append(inbound, g.inbound[i])
+		g.indexOf[a] = positionOf[i]
+	}
+
+	g.points = points
+	g.outbound = outbound
+	g.inbound = inbound
+	g.removed = make([]bool, order)
+	g.removals = 0
+}
+
+func (g *Digraph[T]) HasPoint(a T) bool {
+	_, ok := g.indexOf[a]
+	return ok
+}
+
+// Points returns every point, in the order they were added.
+func (g *Digraph[T]) Points() []T {
+	points := make([]T, 0, g.Order())
+	for _, a := range g.positions() {
+		points = append(points, a)
+	}
+	return points
+}
+
+// Order is the number of points.
+func (g *Digraph[T]) Order() int {
+	return len(g.points) - g.removals
+}
+
+// Size is the number of connections, counting each connection between the same points.
+func (g *Digraph[T]) Size() int {
+	return g.connections
+}
+
+// Connections is the number of connections from "from" to "to".
+func (g *Digraph[T]) Connections(from, to T) int {
+	i, ok := g.indexOf[from]
+	if !ok {
+		return 0
+	}
+	j, ok := g.indexOf[to]
+	if !ok {
+		return 0
+	}
+
+	count := 0
+	for _, k := range g.outbound[i] {
+		if k == j {
+			count++
+		}
+	}
+	return count
+}
+
+// Outbound returns the points "a" is connected to, once for each connection, in the order they were connected.
+func (g *Digraph[T]) Outbound(a T) []T {
+	i, ok := g.indexOf[a]
+	if !ok {
+		return nil
+	}
+	return g.pointsAt(g.outbound[i])
+}
+
+// Inbound returns the points connected to "a", once for each connection, in the order they were connected.
+func (g *Digraph[T]) Inbound(a T) []T {
+	i, ok := g.indexOf[a]
+	if !ok {
+		return nil
+	}
+	return g.pointsAt(g.inbound[i])
+}
+
+// OutDegree is the number of connections leaving "a".
+func (g *Digraph[T]) OutDegree(a T) int {
+	i, ok := g.indexOf[a]
+	if !ok {
+		return 0
+	}
+	return len(g.outbound[i])
+}
+
+// InDegree is the number of connections arriving at "a".
+func (g *Digraph[T]) InDegree(a T) int {
+	i, ok := g.indexOf[a]
+	if !ok {
+		return 0
+	}
+	return len(g.inbound[i])
+}
+
+func (g *Digraph[T]) pointsAt(indexes []int) []T {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	points := make([]T, len(indexes))
+	for k, i := range indexes {
+		points[k] = g.points[i]
+	}
+	return points
+}
+
+// StronglyConnectedComponents partitions the points into groups where every point can reach every other point of
+// the same group. Points that are not part of any cycle are in a group of their own.
+//
+// The groups are given in reverse topological order: no group can reach a group that comes after it. The points of
+// each group are in the order they were added.
+func (g *Digraph[T]) StronglyConnectedComponents() [][]T {
+	//
+	// This is Tarjan's algorithm, with an explicit call stack instead of recursion, so that long paths don't
+	// overflow the goroutine stack.
+	//
+
+	type call struct {
+		point int
+		// next is the position in outbound of the next connection to follow
+		next int
+	}
+
+	order := make([]int, len(g.points)) // the visiting order, starting at 1, or 0 if not visited yet
+	lowest := make([]int, len(g.points))
+	onStack := make([]bool, len(g.points))
+	var stack []int
+	var calls []call
+	var components [][]T
+	visited := 0
+
+	visit := func(i int) {
+		visited++
+		order[i] = visited
+		lowest[i] = visited
+		stack = append(stack, i)
+		onStack[i] = true
+		calls = append(calls, call{point: i})
+	}
+
+	for root := range g.positions() {
+		if order[root] != 0 {
+			continue
+		}
+		visit(root)
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			i := top.point
+
+			if top.next < len(g.outbound[i]) {
+				j := g.outbound[i][top.next]
+				top.next++
+
+				if order[j] == 0 {
+					visit(j)
+				} else if onStack[j] {
+					lowest[i] = min(lowest[i], order[j])
+				}
+				continue
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				caller := calls[len(calls)-1].point
+				lowest[caller] = min(lowest[caller], lowest[i])
+			}
+
+			if lowest[i] == order[i] {
+				var component []int
+				for {
+					j := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[j] = false
+					component = append(component, j)
+					if j == i {
+						break
+					}
+				}
+				slices.Sort(component)
+				components = append(components, g.pointsAt(component))
+			}
+		}
+	}
+
+	return components
+}
+
+// WeaklyConnectedComponents partitions the points into groups that are connected to each other, no matter the
+// direction of the connections. The groups are in the order their first point was added, and so are their points.
+func (g *Digraph[T]) WeaklyConnectedComponents() [][]T {
+	// union-find, where each point starts in a group of its own
+	parent := make([]int, len(g.points))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for i, outbound := range g.outbound {
+		for _, j := range outbound {
+			// the root of each group is its first point
+			a, b := find(i), find(j)
+			if a < b {
+				parent[b] = a
+			} else if b < a {
+				parent[a] = b
+			}
+		}
+	}
+
+	var components [][]T
+	componentOf := make(map[int]int)
+	for i := range g.positions() {
+		root := find(i)
+		k, ok := componentOf[root]
+		if !ok {
+			k = len(components)
+			componentOf[root] = k
+			components = append(components, nil)
+		}
+		components[k] = append(components[k], g.points[i])
+	}
+	return components
+}
+
+// FindCycle returns the points of a cycle, such that each point is connected to the next one, and the last point is
+// connected to the first one. A point connected to itself is a cycle of one point. It returns false if the digraph is
+// acyclic.
+func (g *Digraph[T]) FindCycle() ([]T, bool) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	type call struct {
+		point int
+		next  int
+	}
+
+	state := make([]int, len(g.points))
+	var calls []call
+
+	for root := range g.positions() {
+		if state[root] != unvisited {
+			continue
+		}
+		state[root] = visiting
+		calls = append(calls, call{point: root})
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			i := top.point
+
+			if top.next == len(g.outbound[i]) {
+				state[i] = visited
+				calls = calls[:len(calls)-1]
+				continue
+			}
+
+			j := g.outbound[i][top.next]
+			top.next++
+
+			switch state[j] {
+			case unvisited:
+				state[j] = visiting
+				calls = append(calls, call{point: j})
+			case visiting:
+				// j is on the call stack, so the calls from j up to i form the cycle
+				start := len(calls) - 1
+				for calls[start].point != j {
+					start--
+				}
+
+				cycle := make([]T, 0, len(calls)-start)
+				for _, c := range calls[start:] {
+					cycle = append(cycle, g.points[c.point])
+				}
+				return cycle, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// TopologicalSort orders the points so that every connection goes from a point to another one after it. Among the
+// points that could come next, the one added first comes first. If the digraph has a cycle, it returns an error that
+// wraps ErrCycle.
+func (g *Digraph[T]) TopologicalSort() ([]T, error) {
+	//
+	// This is Kahn's algorithm. Points that are ready are kept sorted by the order they were added, which makes the
+	// result deterministic.
+	//
+
+	inDegree := make([]int, len(g.points))
+	var ready []int
+	for i := range g.positions() {
+		inDegree[i] = len(g.inbound[i])
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	sorted := make([]T, 0, g.Order())
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, g.points[i])
+
+		for _, j := range g.outbound[i] {
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				k, _ := slices.BinarySearch(ready, j)
+				ready = slices.Insert(ready, k, j)
+			}
+		}
+	}
+
+	if len(sorted) < g.Order() {
+		cycle, _ := g.FindCycle()
+		return nil, fmt.Errorf("%w: %v", ErrCycle, cycle)
+	}
+	return sorted, nil
+}
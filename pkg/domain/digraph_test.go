@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDigraph(connections ...[2]string) *Digraph[string] {
+	g := NewDigraph[string]()
+	for _, c := range connections {
+		g.AddConnection(c[0], c[1])
+	}
+	return g
+}
+
+func TestDigraph_AddConnection_AllowsMultipleConnectionsBetweenTheSamePoints(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"a", "b"},
+		[2]string{"b", "a"},
+		[2]string{"a", "c"},
+	)
+
+	assert.Equal(t, g.Order(), 3)
+	assert.Equal(t, g.Size(), 4)
+	assert.Equal(t, g.Connections("a", "b"), 2)
+	assert.Equal(t, g.Connections("b", "a"), 1)
+	assert.Equal(t, g.Connections("c", "a"), 0)
+	assert.Equal(t, g.Connections("a", "z"), 0)
+	assert.Equal(t, g.Outbound("a"), []string{"b", "b", "c"})
+	assert.Equal(t, g.Inbound("b"), []string{"a", "a"})
+}
+
+func TestDigraph_Degrees(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "c"},
+	)
+	g.AddPoint("d")
+
+	tests := []struct {
+		point     string
+		inDegree  int
+		outDegree int
+	}{
+		{"a", 0, 2},
+		{"b", 2, 1},
+		{"c", 2, 1},
+		{"d", 0, 0},
+		{"z", 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.point, func(t *testing.T) {
+			assert.Equal(t, g.InDegree(test.point), test.inDegree)
+			assert.Equal(t, g.OutDegree(test.point), test.outDegree)
+		})
+	}
+
+	assert.True(t, g.HasPoint("d"))
+	assert.False(t, g.HasPoint("z"))
+	assert.Equal(t, g.Points(), []string{"a", "b", "c", "d"})
+}
+
+func TestDigraph_RemoveConnection(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+	)
+
+	assert.True(t, g.RemoveConnection("a", "b"))
+	assert.Equal(t, g.Connections("a", "b"), 1)
+	assert.True(t, g.RemoveConnection("a", "b"))
+	assert.False(t, g.RemoveConnection("a", "b"))
+	assert.False(t, g.RemoveConnection("c", "b"))
+	assert.False(t, g.RemoveConnection("z", "b"))
+
+	assert.Equal(t, g.Size(), 1)
+	assert.Equal(t, g.Points(), []string{"a", "b", "c"})
+	assert.Equal(t, g.OutDegree("a"), 0)
+	assert.Equal(t, g.InDegree("b"), 0)
+}
+
+func TestDigraph_RemovePoint(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"b", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "b"},
+		[2]string{"c", "d"},
+	)
+
+	assert.True(t, g.RemovePoint("b"))
+	assert.False(t, g.RemovePoint("b"))
+
+	assert.False(t, g.HasPoint("b"))
+	assert.Equal(t, g.Points(), []string{"a", "c", "d"})
+	assert.Equal(t, g.Order(), 3)
+	assert.Equal(t, g.Size(), 1)
+	assert.Equal(t, g.OutDegree("a"), 0)
+	assert.Equal(t, g.Inbound("c"), []string(nil))
+	assert.Equal(t, g.Outbound("c"), []string{"d"})
+
+	// so many points were removed that the rest were moved to new positions
+	assert.True(t, g.RemovePoint("a"))
+	assert.Equal(t, g.Points(), []string{"c", "d"})
+	assert.Equal(t, g.Outbound("c"), []string{"d"})
+	assert.Equal(t, g.Inbound("d"), []string{"c"})
+
+	g.AddConnection("d", "b")
+	assert.Equal(t, g.Points(), []string{"c", "d", "b"})
+	sorted, err := g.TopologicalSort()
+	assert.NoError(t, err)
+	assert.Equal(t, sorted, []string{"c", "d", "b"})
+}
+
+func TestDigraph_StronglyConnectedComponents(t *testing.T) {
+	tests := []struct {
+		name        string
+		connections [][2]string
+		want        [][]string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:        "path",
+			connections: [][2]string{{"a", "b"}, {"b", "c"}},
+			want:        [][]string{{"c"}, {"b"}, {"a"}},
+		},
+		{
+			name:        "loop",
+			connections: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+			want:        [][]string{{"a", "b", "c"}},
+		},
+		{
+			name: "two loops joined by a connection",
+			connections: [][2]string{
+				{"a", "b"}, {"b", "a"}, {"b", "c"}, {"c", "d"}, {"d", "e"}, {"e", "c"},
+			},
+			want: [][]string{{"c", "d", "e"}, {"a", "b"}},
+		},
+		{
+			name:        "point connected to itself",
+			connections: [][2]string{{"a", "a"}, {"a", "b"}},
+			want:        [][]string{{"b"}, {"a"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestDigraph(test.connections...)
+			assert.Equal(t, g.StronglyConnectedComponents(), test.want)
+		})
+	}
+}
+
+func TestDigraph_WeaklyConnectedComponents(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"c", "d"},
+		[2]string{"e", "b"},
+		[2]string{"d", "f"},
+		[2]string{"f", "c"},
+	)
+	g.AddPoint("g")
+
+	assert.Equal(t, g.WeaklyConnectedComponents(), [][]string{{"a", "b", "e"}, {"c", "d", "f"}, {"g"}})
+}
+
+func TestDigraph_FindCycle(t *testing.T) {
+	tests := []struct {
+		name        string
+		connections [][2]string
+		want        []string
+	}{
+		{
+			name:        "acyclic",
+			connections: [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}},
+		},
+		{
+			name:        "loop",
+			connections: [][2]string{{"x", "a"}, {"a", "b"}, {"b", "c"}, {"c", "a"}},
+			want:        []string{"a", "b", "c"},
+		},
+		{
+			name:        "point connected to itself",
+			connections: [][2]string{{"a", "b"}, {"b", "b"}},
+			want:        []string{"b"},
+		},
+		{
+			name:        "back and forth",
+			connections: [][2]string{{"a", "b"}, {"b", "a"}},
+			want:        []string{"a", "b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTestDigraph(test.connections...)
+
+			cycle, ok := g.FindCycle()
+
+			assert.Equal(t, ok, test.want != nil)
+			assert.Equal(t, cycle, test.want)
+			for i := range cycle {
+				assert.Positive(t, g.Connections(cycle[i], cycle[(i+1)%len(cycle)]))
+			}
+		})
+	}
+}
+
+func TestDigraph_TopologicalSort(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"c", "d"},
+		[2]string{"a", "c"},
+		[2]string{"b", "c"},
+		[2]string{"a", "b"},
+		[2]string{"a", "b"},
+	)
+	g.AddPoint("e")
+
+	sorted, err := g.TopologicalSort()
+
+	assert.NoError(t, err)
+	assert.Equal(t, sorted, []string{"a", "b", "c", "d", "e"})
+}
+
+func TestDigraph_TopologicalSort_FailsIfTheresACycle(t *testing.T) {
+	g := newTestDigraph(
+		[2]string{"a", "b"},
+		[2]string{"b", "c"},
+		[2]string{"c", "b"},
+	)
+
+	sorted, err := g.TopologicalSort()
+
+	assert.Nil(t, sorted)
+	assert.ErrorIs(t, err, ErrCycle)
+	assert.EqualError(t, err, "the digraph has a cycle: [b c]")
+}
+
+func TestDigraph_LongPathDoesNotOverflowTheStack(t *testing.T) {
+	const length = 1_000_000
+
+	g := NewDigraph[int]()
+	for i := 0; i < length; i++ {
+		g.AddConnection(i, i+1)
+	}
+
+	assert.Len(t, g.StronglyConnectedComponents(), length+1)
+	assert.Len(t, g.WeaklyConnectedComponents(), 1)
+	_, ok := g.FindCycle()
+	assert.False(t, ok)
+
+	g.AddConnection(length, 0)
+	cycle, ok := g.FindCycle()
+	assert.True(t, ok)
+	assert.Len(t, cycle, length+1)
+	assert.Len(t, g.StronglyConnectedComponents(), 1)
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	ErrUnknownCabinClass       = errors.New("unknown cabin class")
+	ErrInvalidEmissionsMethod  = errors.New("invalid emissions method")
+	ErrEmissionsWithoutAirport = errors.New("unable to estimate emissions - unknown coordinates of airport")
+)
+
+// CabinClass is the class of service a traveler flies in, which tells how much of the emissions of a flight are
+// theirs, since premium seats take up more room.
+type CabinClass string
+
+const (
+	CabinClassEconomy        CabinClass = "economy"
+	CabinClassPremiumEconomy CabinClass = "premium_economy"
+	CabinClassBusiness       CabinClass = "business"
+	CabinClassFirst          CabinClass = "first"
+)
+
+// EmissionsBand is the emission factor of the flight legs up to a given distance.
+type EmissionsBand struct {
+	// MaxDistance is the longest distance in kilometers, inclusive, the band applies to. Zero means that there's no
+	// limit, which is only allowed for the last band.
+	MaxDistance float64 `json:"max_distance_km"`
+	// Factor is how many kilograms of CO2 are emitted for each kilometer an economy class traveler flies
+	Factor float64 `json:"kg_co2_per_km"`
+}
+
+// EmissionsMethod tells how the CO2 emissions of a single traveler are estimated from the great-circle distance of a
+// flight leg:
+//
+//  1. The distance is increased by the DistanceUplift, since flights rarely follow the great circle, and may be
+//     delayed by holding patterns and taxiing.
+//  2. The distance is multiplied by the factor of the first band it fits in, as a whole. Short flights have higher
+//     factors, since they spend more of their time taking off and climbing.
+//  3. The result is multiplied by the multiplier of the cabin class.
+type EmissionsMethod struct {
+	// DistanceUplift is such as 0.08 for 8% more than the great-circle distance
+	DistanceUplift float64 `json:"distance_uplift"`
+	// Bands are sorted by their MaxDistance, and the last one has no limit
+	Bands        []EmissionsBand        `json:"bands"`
+	CabinClasses map[CabinClass]float64 `json:"cabin_classes"`
+}
+
+// DefaultEmissionsMethod has factors in the range of those commonly published for passenger flights, such as by the UK
+// government for company reporting. They are estimates for reporting, and must not be taken as the emissions of any
+// actual flight.
+var DefaultEmissionsMethod = EmissionsMethod{
+	DistanceUplift: 0.08,
+	Bands: []EmissionsBand{
+		{MaxDistance: 500, Factor: 0.25},
+		{MaxDistance: 3700, Factor: 0.18},
+		{Factor: 0.15},
+	},
+	CabinClasses: map[CabinClass]float64{
+		CabinClassEconomy:        1,
+		CabinClassPremiumEconomy: 1.6,
+		CabinClassBusiness:       2.9,
+		CabinClassFirst:          4,
+	},
+}
+
+// ParseEmissionsMethod decodes and validates an emissions method in JSON, such as:
+//
+//	{
+//	    "distance_uplift": 0.08,
+//	    "bands": [{"max_distance_km": 500, "kg_co2_per_km": 0.25}, {"kg_co2_per_km": 0.2}],
+//	    "cabin_classes": {"economy": 1, "business": 2.9}
+//	}
+func ParseEmissionsMethod(data []byte) (EmissionsMethod, error) {
+	var method EmissionsMethod
+	if err := json.Unmarshal(data, &method); err != nil {
+		return method, fmt.Errorf("%w: %w", ErrInvalidEmissionsMethod, err)
+	}
+	if err := method.Validate(); err != nil {
+		return method, err
+	}
+	return method, nil
+}
+
+func (m EmissionsMethod) Validate() error {
+	if m.DistanceUplift < 0 {
+		return fmt.Errorf("%w: the distance uplift cannot be negative", ErrInvalidEmissionsMethod)
+	}
+	if len(m.Bands) == 0 || m.Bands[len(m.Bands)-1].MaxDistance != 0 {
+		return fmt.Errorf("%w: the last band must have no maximum distance", ErrInvalidEmissionsMethod)
+	}
+
+	var previous float64
+	for i, band := range m.Bands {
+		if band.Factor <= 0 {
+			return fmt.Errorf("%w: the factor of band %v must be positive", ErrInvalidEmissionsMethod, i)
+		}
+		if i < len(m.Bands)-1 && band.MaxDistance <= previous {
+			return fmt.Errorf("%w: the maximum distance of band %v must be greater than the one before it",
+				ErrInvalidEmissionsMethod, i)
+		}
+		previous = band.MaxDistance
+	}
+
+	if _, ok := m.CabinClasses[CabinClassEconomy]; !ok {
+		return fmt.Errorf("%w: the economy cabin class is required", ErrInvalidEmissionsMethod)
+	}
+	for class, multiplier := range m.CabinClasses {
+		if multiplier <= 0 {
+			return fmt.Errorf("%w: the multiplier of cabin class %v must be positive", ErrInvalidEmissionsMethod, class)
+		}
+	}
+	return nil
+}
+
+// Estimate is the emissions of a single traveler flying a great-circle distance, in kilometers, in the cabin class.
+// The distance is rounded to the kilometer, and the CO2 to a tenth of a kilogram.
+func (m EmissionsMethod) Estimate(greatCircleDistance float64, cabinClass CabinClass) (model.Emissions, error) {
+	multiplier, ok := m.CabinClasses[cabinClass]
+	if !ok {
+		return model.Emissions{}, fmt.Errorf("%w %q", ErrUnknownCabinClass, cabinClass)
+	}
+
+	distance := greatCircleDistance * (1 + m.DistanceUplift)
+	factor := m.Bands[len(m.Bands)-1].Factor
+	for _, band := range m.Bands {
+		if distance <= band.MaxDistance {
+			factor = band.Factor
+			break
+		}
+	}
+
+	return model.Emissions{
+		Distance: math.Round(distance),
+		CO2:      math.Round(distance*factor*multiplier*10) / 10,
+	}, nil
+}
+
+// EstimateEmissions attaches the emissions estimate of a single traveler to every flight leg of the flight path, and
+// totals them on the flight path. It fails with ErrEmissionsWithoutAirport if the locator does not know one of the
+// airports, in which case no estimate is attached at all.
+func EstimateEmissions(
+	flightPath *model.FlightPath, locator AirportLocator, method EmissionsMethod, cabinClass CabinClass,
+) error {
+	estimates := make([]model.Emissions, len(flightPath.FlightLegs))
+	var total model.Emissions
+
+	for i, leg := range flightPath.FlightLegs {
+		departure, ok := locator.Coordinates(leg.Departure)
+		if !ok {
+			return fmt.Errorf("%w %v", ErrEmissionsWithoutAirport, leg.Departure)
+		}
+		arrival, ok := locator.Coordinates(leg.Arrival)
+		if !ok {
+			return fmt.Errorf("%w %v", ErrEmissionsWithoutAirport, leg.Arrival)
+		}
+
+		estimate, err := method.Estimate(departure.DistanceTo(arrival), cabinClass)
+		if err != nil {
+			return err
+		}
+		estimates[i] = estimate
+		total.Distance += estimate.Distance
+		total.CO2 += estimate.CO2
+	}
+
+	for i := range flightPath.FlightLegs {
+		flightPath.FlightLegs[i].Emissions = &estimates[i]
+	}
+	// the estimates are already rounded, so this only drops the floating point error of their sum
+	total.CO2 = math.Round(total.CO2*10) / 10
+	flightPath.Emissions = &total
+	return nil
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestEmissionsMethod_Estimate(t *testing.T) {
+	tests := []struct {
+		name       string
+		distance   float64
+		cabinClass CabinClass
+		want       model.Emissions
+	}{
+		{"short", 400, CabinClassEconomy, model.Emissions{Distance: 432, CO2: 108}},
+		{"uplift moves it to the next band", 470, CabinClassEconomy, model.Emissions{Distance: 508, CO2: 91.4}},
+		{"medium", 1000, CabinClassEconomy, model.Emissions{Distance: 1080, CO2: 194.4}},
+		{"long", 8000, CabinClassEconomy, model.Emissions{Distance: 8640, CO2: 1296}},
+		{"premium economy", 8000, CabinClassPremiumEconomy, model.Emissions{Distance: 8640, CO2: 2073.6}},
+		{"business", 8000, CabinClassBusiness, model.Emissions{Distance: 8640, CO2: 3758.4}},
+		{"first", 8000, CabinClassFirst, model.Emissions{Distance: 8640, CO2: 5184}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DefaultEmissionsMethod.Estimate(test.distance, test.cabinClass)
+			assert.NoError(t, err)
+			assert.Equal(t, got, test.want)
+		})
+	}
+}
+
+func TestEmissionsMethod_Estimate_UnknownCabinClass(t *testing.T) {
+	_, err := DefaultEmissionsMethod.Estimate(1000, "coach")
+	assert.ErrorIs(t, err, ErrUnknownCabinClass)
+	assert.EqualError(t, err, `unknown cabin class "coach"`)
+}
+
+func TestParseEmissionsMethod(t *testing.T) {
+	data, err := os.ReadFile("../../data/emissions_method.json")
+	assert.NoError(t, err)
+
+	method, err := ParseEmissionsMethod(data)
+	assert.NoError(t, err)
+	assert.Equal(t, method, DefaultEmissionsMethod)
+}
+
+func TestParseEmissionsMethod_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantError string
+	}{
+		{
+			name:      "malformed",
+			data:      `{"bands": 5}`,
+			wantError: "invalid emissions method: json: cannot unmarshal number into Go struct field EmissionsMethod.bands of type []domain.EmissionsBand",
+		},
+		{
+			name:      "negative uplift",
+			data:      `{"distance_uplift": -0.1, "bands": [{"kg_co2_per_km": 0.2}], "cabin_classes": {"economy": 1}}`,
+			wantError: "invalid emissions method: the distance uplift cannot be negative",
+		},
+		{
+			name:      "no bands",
+			data:      `{"cabin_classes": {"economy": 1}}`,
+			wantError: "invalid emissions method: the last band must have no maximum distance",
+		},
+		{
+			name: "unsorted bands",
+			data: `{"bands": [{"max_distance_km": 3700, "kg_co2_per_km": 0.18}, {"max_distance_km": 500, "kg_co2_per_km": 0.25},` +
+				` {"kg_co2_per_km": 0.15}], "cabin_classes": {"economy": 1}}`,
+			wantError: "invalid emissions method: the maximum distance of band 1 must be greater than the one before it",
+		},
+		{
+			name:      "zero factor",
+			data:      `{"bands": [{"kg_co2_per_km": 0}], "cabin_classes": {"economy": 1}}`,
+			wantError: "invalid emissions method: the factor of band 0 must be positive",
+		},
+		{
+			name:      "no economy",
+			data:      `{"bands": [{"kg_co2_per_km": 0.2}], "cabin_classes": {"business": 3}}`,
+			wantError: "invalid emissions method: the economy cabin class is required",
+		},
+		{
+			name:      "negative multiplier",
+			data:      `{"bands": [{"kg_co2_per_km": 0.2}], "cabin_classes": {"economy": 1, "first": -4}}`,
+			wantError: "invalid emissions method: the multiplier of cabin class first must be positive",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseEmissionsMethod([]byte(test.data))
+			assert.ErrorIs(t, err, ErrInvalidEmissionsMethod)
+			assert.EqualError(t, err, test.wantError)
+		})
+	}
+}
+
+func TestEstimateEmissions(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "DEN"},
+			{Departure: "DEN", Arrival: "ORD"},
+			{Departure: "ORD", Arrival: "GSO"},
+		},
+	}
+
+	err := EstimateEmissions(flightPath, testAirports, DefaultEmissionsMethod, CabinClassBusiness)
+	assert.NoError(t, err)
+
+	var total model.Emissions
+	for _, leg := range flightPath.FlightLegs {
+		assert.NotNil(t, leg.Emissions)
+		total.Distance += leg.Emissions.Distance
+		total.CO2 += leg.Emissions.CO2
+	}
+	assert.Equal(t, flightPath.FlightLegs[0].Emissions, &model.Emissions{Distance: 1677, CO2: 875.4})
+	assert.Equal(t, flightPath.Emissions.Distance, total.Distance)
+	assert.InDelta(t, flightPath.Emissions.CO2, total.CO2, 1e-9)
+}
+
+func TestEstimateEmissions_UnknownAirport(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "XXX"},
+			{Departure: "XXX", Arrival: "GSO"},
+		},
+	}
+
+	err := EstimateEmissions(flightPath, testAirports, DefaultEmissionsMethod, CabinClassEconomy)
+	assert.ErrorIs(t, err, ErrEmissionsWithoutAirport)
+	assert.EqualError(t, err, "unable to estimate emissions - unknown coordinates of airport XXX")
+	assert.Nil(t, flightPath.Emissions)
+	assert.Nil(t, flightPath.FlightLegs[0].Emissions)
+}
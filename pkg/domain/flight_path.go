@@ -1,37 +1,202 @@
 package domain
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/felipead/flight-path-tracker/pkg/model"
 )
 
+// cancellationCheckInterval is how many iterations the long-running loops below perform between checks for
+// context cancellation. Checking on every iteration would add needless overhead for very large inputs.
+const cancellationCheckInterval = 1024
+
+var (
+	ErrEmptyFlightPath        = errors.New("empty flight path")
+	ErrInvalidFlightPath      = errors.New("invalid flight path")
+	ErrDisconnectedFlightPath = errors.New("disconnected flight path")
+	ErrDuplicateFlightLeg     = errors.New("duplicate flight leg")
+	ErrUnknownRoute           = errors.New("no airline is known to fly the route")
+)
+
+// DuplicatePolicy tells what to do with a flight leg that is given more than once. Flight legs are only duplicates if
+// their airports and their departure and arrival times are the same, so flight legs between the same airports at
+// different times are distinct, and conflict with each other as usual.
+type DuplicatePolicy int
+
+const (
+	// RejectDuplicates fails with ErrDuplicateFlightLeg
+	RejectDuplicates DuplicatePolicy = iota
+	// SkipDuplicates silently keeps only the first of the duplicates
+	SkipDuplicates
+	// WarnDuplicates keeps only the first of the duplicates, and adds a warning to the flight path for each one of
+	// the others
+	WarnDuplicates
+)
+
+// RouteNetwork tells which airports some airline flies nonstop between, such as according to a route dataset.
+type RouteNetwork interface {
+	HasRoute(departure, arrival model.AirportCode) bool
+}
+
+// CalculationOptions change how a flight path is calculated. The zero value is the default behavior.
+type CalculationOptions struct {
+	Duplicates DuplicatePolicy
+	// GapRanker ranks the flight legs that are suggested when the flight path is disconnected. If nil, they are only
+	// ranked by their schedule.
+	GapRanker GapRanker
+	// Routes, if given, is checked for the route of every flight leg. A flight leg whose route is unknown adds a
+	// warning to the flight path, or fails with ErrUnknownRoute if StrictRoutes is set.
+	Routes       RouteNetwork
+	StrictRoutes bool
+}
+
 func CalculateFlightPath(flightLegs []model.FlightLeg) (*model.FlightPath, error) {
+	return CalculateFlightPathContext(context.Background(), flightLegs)
+}
+
+// CalculateFlightPathContext is like CalculateFlightPath, but it stops as soon as possible if the context is canceled
+// or its deadline is exceeded, returning an error that wraps the context error.
+func CalculateFlightPathContext(ctx context.Context, flightLegs []model.FlightLeg) (*model.FlightPath, error) {
+	return CalculateFlightPathWithOptions(ctx, flightLegs, CalculationOptions{})
+}
+
+// CalculateFlightPathWithOptions is like CalculateFlightPathContext, but calculated according to the given options.
+func CalculateFlightPathWithOptions(
+	ctx context.Context, flightLegs []model.FlightLeg, options CalculationOptions,
+) (*model.FlightPath, error) {
 	if len(flightLegs) == 0 {
-		return nil, errors.New("empty flight path")
+		return nil, ErrEmptyFlightPath
+	}
+
+	builder := NewFlightPathBuilderWithOptions(options)
+	defer builder.Release()
+
+	for i, leg := range flightLegs {
+		if err := checkCanceled(ctx, i); err != nil {
+			return nil, err
+		}
+
+		if err := builder.AddFlightLeg(leg); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(ctx)
+}
+
+// FlightPathBuilder calculates a flight path out of flight legs that are added one at a time, such as while they are
+// decoded from a stream. A flight leg that conflicts with the ones added before it is rejected right away, so that
+// the caller can stop early.
+//
+// As long as every airport code is a plain IATA code, made of three uppercase letters, the flight legs are connected
+// by a packedPath. Otherwise, they are moved into the generic Path.
+type FlightPathBuilder struct {
+	path            airportPath
+	legsByDeparture map[model.AirportCode]addedFlightLeg
+	options         CalculationOptions
+	warnings        []model.Warning
+}
+
+// addedFlightLeg is a flight leg along with the order it was added in
+type addedFlightLeg struct {
+	model.FlightLeg
+	position int
+}
+
+func NewFlightPathBuilder() *FlightPathBuilder {
+	return NewFlightPathBuilderWithOptions(CalculationOptions{})
+}
+
+func NewFlightPathBuilderWithOptions(options CalculationOptions) *FlightPathBuilder {
+	return &FlightPathBuilder{
+		path:            newPackedPath(),
+		legsByDeparture: make(map[model.AirportCode]addedFlightLeg),
+		options:         options,
 	}
+}
 
-	path := NewPath[model.AirportCode]()
+func (b *FlightPathBuilder) AddFlightLeg(leg model.FlightLeg) error {
+	if existing, ok := b.legsByDeparture[leg.Departure]; ok && sameFlightLeg(existing.FlightLeg, leg) {
+		return b.addDuplicate(leg)
+	}
 
-	for _, leg := range flightLegs {
-		err := path.AddConnection(leg.Departure, leg.Arrival)
-		if err != nil {
-			return nil, fmt.Errorf("invalid flight path; %w", err)
+	var unknownRoute error
+	if b.options.Routes != nil && !b.options.Routes.HasRoute(leg.Departure, leg.Arrival) {
+		unknownRoute = fmt.Errorf("%w from %v to %v", ErrUnknownRoute, leg.Departure, leg.Arrival)
+		if b.options.StrictRoutes {
+			return fmt.Errorf("%w; %w", ErrInvalidFlightPath, unknownRoute)
 		}
 	}
 
-	start, err := path.FindStart()
+	err := b.path.AddConnection(leg.Departure, leg.Arrival)
+	if errors.Is(err, errUnpackableAirportCode) {
+		b.unpack()
+		err = b.path.AddConnection(leg.Departure, leg.Arrival)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid flight path; %w", err)
+		return fmt.Errorf("%w; %w", ErrInvalidFlightPath, err)
+	}
+
+	b.legsByDeparture[leg.Departure] = addedFlightLeg{FlightLeg: leg, position: len(b.legsByDeparture)}
+	if unknownRoute != nil {
+		b.warnings = append(b.warnings, model.Warning{Finding: model.Finding{Err: unknownRoute}, FlightLeg: leg})
+	}
+	return nil
+}
+
+func (b *FlightPathBuilder) addDuplicate(leg model.FlightLeg) error {
+	err := fmt.Errorf("%w from %v to %v", ErrDuplicateFlightLeg, leg.Departure, leg.Arrival)
+
+	switch b.options.Duplicates {
+	case SkipDuplicates:
+		return nil
+	case WarnDuplicates:
+		b.warnings = append(b.warnings, model.Warning{Finding: model.Finding{Err: err}, FlightLeg: leg})
+		return nil
+	default:
+		return fmt.Errorf("%w; %w", ErrInvalidFlightPath, err)
+	}
+}
+
+func (b *FlightPathBuilder) unpack() {
+	packed := b.path.(*packedPath)
+	b.path = packed.unpack()
+	packed.release()
+}
+
+// Length is the number of flight legs added so far.
+func (b *FlightPathBuilder) Length() int {
+	return len(b.legsByDeparture)
+}
+
+// Build sorts the flight legs added so far. It stops as soon as possible if the context is canceled or its deadline
+// is exceeded, returning an error that wraps the context error. The builder must not be used afterwards.
+func (b *FlightPathBuilder) Build(ctx context.Context) (*model.FlightPath, error) {
+	defer b.Release()
+
+	if b.Length() == 0 {
+		return nil, ErrEmptyFlightPath
 	}
 
-	end, err := path.FindEnd()
+	start, err := b.path.FindStart()
 	if err != nil {
-		return nil, fmt.Errorf("invalid flight path; %w", err)
+		return nil, fmt.Errorf("%w; %w", ErrInvalidFlightPath, err)
 	}
 
-	sortedLegs, err := sortFlightLegs(path, start, end)
+	end, err := b.path.FindEnd()
+	if err != nil {
+		return nil, fmt.Errorf("%w; %w", ErrInvalidFlightPath, err)
+	}
+
+	sortedLegs, err := sortFlightLegs(ctx, b.path, b.legsByDeparture, start, end)
+	if errors.Is(err, ErrDisconnectedFlightPath) {
+		return nil, &DisconnectedFlightPathError{
+			err:                 err,
+			SuggestedFlightLegs: suggestMissingFlightLegs(b.legsByDeparture, b.options.GapRanker),
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -40,10 +205,27 @@ func CalculateFlightPath(flightLegs []model.FlightLeg) (*model.FlightPath, error
 		Origin:      start,
 		Destination: end,
 		FlightLegs:  sortedLegs,
+		Warnings:    b.warnings,
 	}, nil
 }
 
-func sortFlightLegs(path *Path[model.AirportCode], start, end model.AirportCode) ([]model.FlightLeg, error) {
+// Release gives the memory of the builder back to the pool when it will not be built, such as when a flight leg was
+// rejected. It is safe to call it more than once, or after Build. The builder must not be used afterwards.
+func (b *FlightPathBuilder) Release() {
+	if packed, ok := b.path.(*packedPath); ok {
+		packed.release()
+	}
+	b.path = nil
+}
+
+// sortFlightLegs walks the path from start to end. The sorted flight legs are taken from legsByDeparture, so that
+// anything besides the airport codes, such as the departure and arrival times, is preserved.
+func sortFlightLegs(
+	ctx context.Context,
+	path airportPath,
+	legsByDeparture map[model.AirportCode]addedFlightLeg,
+	start, end model.AirportCode,
+) ([]model.FlightLeg, error) {
 	sortedLegs := make([]model.FlightLeg, 0, path.Length())
 
 	//
@@ -53,19 +235,38 @@ func sortFlightLegs(path *Path[model.AirportCode], start, end model.AirportCode)
 	//
 
 	this := start
-	for this != end {
-		next := path.GetNext(this)
+	for i := 0; this != end; i++ {
+		if err := checkCanceled(ctx, i); err != nil {
+			return nil, err
+		}
 
-		if next == "" {
-			return nil, fmt.Errorf("disconnected flight path; there's no flight leg leaving airport %v", this)
+		next, ok := path.Next(this)
+
+		if !ok {
+			return nil, fmt.Errorf("%w; there's no flight leg leaving airport %v", ErrDisconnectedFlightPath, this)
 		}
 
-		sortedLegs = append(sortedLegs, model.FlightLeg{
-			Departure: this,
-			Arrival:   next,
-		})
+		sortedLegs = append(sortedLegs, legsByDeparture[this].FlightLeg)
 		this = next
 	}
 
+	// The start and the end might belong to the same partition, in which case the walk succeeds, but misses the
+	// flight legs of the other partitions.
+	if missing := path.Length() - len(sortedLegs); missing > 0 {
+		return nil, fmt.Errorf("%w; %v flight legs are not connected to the path from airport %v to %v",
+			ErrDisconnectedFlightPath, missing, start, end)
+	}
+
 	return sortedLegs, nil
 }
+
+// checkCanceled returns a non-nil error if the context is done, but only checks it every few iterations.
+func checkCanceled(ctx context.Context, iteration int) error {
+	if iteration%cancellationCheckInterval != 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("flight path calculation aborted; %w", err)
+	}
+	return nil
+}
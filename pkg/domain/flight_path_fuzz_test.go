@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// fuzzAirportCodes is a small set of airport codes, so that fuzzed flight legs often share airports and form paths,
+// loops and branches. The lowercase ones cannot be packed.
+var fuzzAirportCodes = []model.AirportCode{
+	"ATL", "EWR", "GSO", "IND", "JFK", "ORD", "SFO", "YUL", "GRU", "CNF", "MIA", "LHR", "sfo", "jfk",
+}
+
+// fuzzFlightLegs turns every two bytes into a flight leg between two of the fuzzAirportCodes
+func fuzzFlightLegs(data []byte) []model.FlightLeg {
+	legs := make([]model.FlightLeg, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		legs = append(legs, model.FlightLeg{
+			Departure: fuzzAirportCodes[int(data[i])%len(fuzzAirportCodes)],
+			Arrival:   fuzzAirportCodes[int(data[i+1])%len(fuzzAirportCodes)],
+		})
+	}
+	return legs
+}
+
+func FuzzCalculateFlightPath(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 1})
+	f.Add([]byte{1, 2, 0, 1})
+	f.Add([]byte{0, 1, 1, 0})
+	f.Add([]byte{0, 1, 0, 2})
+	f.Add([]byte{0, 1, 2, 3})
+	f.Add([]byte{6, 0, 0, 1, 1, 12})
+	f.Add([]byte{4, 4})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		legs := fuzzFlightLegs(data)
+
+		flightPath, err := CalculateFlightPath(legs)
+
+		if err != nil {
+			assert.Nil(t, flightPath)
+			if !errors.Is(err, ErrEmptyFlightPath) && !errors.Is(err, ErrInvalidFlightPath) &&
+				!errors.Is(err, ErrDisconnectedFlightPath) {
+				t.Fatalf("CalculateFlightPath() returned an unexpected error: %v", err)
+			}
+			return
+		}
+
+		assertValidFlightPath(t, flightPath, legs)
+	})
+}
+
+// assertValidFlightPath checks that the flight path sorts every one of the flight legs into a single path
+func assertValidFlightPath(t *testing.T, flightPath *model.FlightPath, legs []model.FlightLeg) {
+	t.Helper()
+
+	sortedLegs := flightPath.FlightLegs
+	assert.Equal(t, sortByDeparture(sortedLegs), sortByDeparture(legs), "the flight legs are not the same")
+
+	assert.Equal(t, sortedLegs[0].Departure, flightPath.Origin)
+	assert.Equal(t, sortedLegs[len(sortedLegs)-1].Arrival, flightPath.Destination)
+
+	for i := 1; i < len(sortedLegs); i++ {
+		assert.Equal(t, sortedLegs[i].Departure, sortedLegs[i-1].Arrival, "flight leg %v is not connected", i)
+	}
+
+	for _, leg := range legs {
+		assert.NotEqual(t, leg.Arrival, flightPath.Origin, "the origin has an inbound flight leg")
+		assert.NotEqual(t, leg.Departure, flightPath.Destination, "the destination has an outbound flight leg")
+	}
+}
+
+func sortByDeparture(legs []model.FlightLeg) []model.FlightLeg {
+	sorted := slices.Clone(legs)
+	slices.SortFunc(sorted, func(a, b model.FlightLeg) int {
+		return strings.Compare(string(a.Departure), string(b.Departure))
+	})
+	return sorted
+}
+
+// propertyTestSeeds is how many random chains each property is checked against
+const propertyTestSeeds = 100
+
+func TestCalculateFlightPath_Properties(t *testing.T) {
+	for seed := int64(0); seed < propertyTestSeeds; seed++ {
+		g := generator.New(seed)
+		length := 1 + int(seed)*37%2000
+
+		chain := g.Chain(length)
+		legs := slices.Clone(chain)
+		g.Shuffle(legs)
+
+		flightPath, err := CalculateFlightPath(legs)
+		assert.NoError(t, err)
+
+		assertValidFlightPath(t, flightPath, legs)
+
+		// the chain is the only valid sorting of its own flight legs
+		assert.Equal(t, flightPath.FlightLegs, chain)
+
+		path := NewPath[model.AirportCode]()
+		for _, leg := range legs {
+			assert.NoError(t, path.AddConnection(leg.Departure, leg.Arrival))
+		}
+		assert.Equal(t, len(flightPath.FlightLegs), path.Length())
+	}
+}
+
+func TestCalculateFlightPath_PropertySameResultRegardlessOfOrder(t *testing.T) {
+	g := generator.New(42)
+	legs := g.Chain(500)
+
+	want, err := CalculateFlightPath(legs)
+	assert.NoError(t, err)
+
+	for i := 0; i < propertyTestSeeds; i++ {
+		shuffled := slices.Clone(legs)
+		g.Shuffle(shuffled)
+
+		got, err := CalculateFlightPath(shuffled)
+		assert.NoError(t, err)
+		assert.Equal(t, got, want)
+	}
+}
+
+func TestCalculateFlightPath_PropertyPackedAndGenericPathsAgree(t *testing.T) {
+	for seed := int64(0); seed < propertyTestSeeds; seed++ {
+		// only the uppercase airport codes, so that the packed path is used all along
+		data := make([]byte, 2*(1+seed%10))
+		rand.New(rand.NewSource(seed)).Read(data)
+		for i := range data {
+			data[i] %= 12
+		}
+		legs := fuzzFlightLegs(data)
+
+		packed := NewFlightPathBuilder()
+		generic := &FlightPathBuilder{
+			path:            NewPath[model.AirportCode](),
+			legsByDeparture: make(map[model.AirportCode]addedFlightLeg),
+		}
+
+		var packedErr, genericErr error
+		for _, leg := range legs {
+			if packedErr == nil {
+				packedErr = packed.AddFlightLeg(leg)
+			}
+			if genericErr == nil {
+				genericErr = generic.AddFlightLeg(leg)
+			}
+		}
+		assert.Equal(t, packedErr, genericErr)
+		if packedErr != nil {
+			continue
+		}
+		assert.IsType(t, packed.path, &packedPath{})
+
+		packedFlightPath, packedErr := packed.Build(context.Background())
+		genericFlightPath, genericErr := generic.Build(context.Background())
+
+		// with more than one partition, the error depends on which start and end are found first
+		assert.Equal(t, packedErr == nil, genericErr == nil)
+		assert.Equal(t, packedFlightPath, genericFlightPath)
+	}
+}
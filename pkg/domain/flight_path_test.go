@@ -1,10 +1,13 @@
 package domain
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/felipead/flight-path-tracker/pkg/generator"
 	"github.com/felipead/flight-path-tracker/pkg/model"
 )
 
@@ -27,7 +30,7 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "SFO",
 			wantDestination: "CNF",
 			wantSortedLegs: []model.FlightLeg{
-				{"SFO", "CNF"},
+				{Departure: "SFO", Arrival: "CNF"},
 			},
 		},
 		{
@@ -45,8 +48,8 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "SFO",
 			wantDestination: "MIA",
 			wantSortedLegs: []model.FlightLeg{
-				{"SFO", "CNF"},
-				{"CNF", "MIA"},
+				{Departure: "SFO", Arrival: "CNF"},
+				{Departure: "CNF", Arrival: "MIA"},
 			},
 		},
 		{
@@ -64,8 +67,8 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "SFO",
 			wantDestination: "EWR",
 			wantSortedLegs: []model.FlightLeg{
-				{"SFO", "ATL"},
-				{"ATL", "EWR"},
+				{Departure: "SFO", Arrival: "ATL"},
+				{Departure: "ATL", Arrival: "EWR"},
 			},
 		},
 		{
@@ -91,10 +94,10 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "SFO",
 			wantDestination: "EWR",
 			wantSortedLegs: []model.FlightLeg{
-				{"SFO", "ATL"},
-				{"ATL", "GSO"},
-				{"GSO", "IND"},
-				{"IND", "EWR"},
+				{Departure: "SFO", Arrival: "ATL"},
+				{Departure: "ATL", Arrival: "GSO"},
+				{Departure: "GSO", Arrival: "IND"},
+				{Departure: "IND", Arrival: "EWR"},
 			},
 		},
 		{
@@ -132,13 +135,13 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "CNF",
 			wantDestination: "LHR",
 			wantSortedLegs: []model.FlightLeg{
-				{"CNF", "GRU"},
-				{"GRU", "MIA"},
-				{"MIA", "ORD"},
-				{"ORD", "SFO"},
-				{"SFO", "YUL"},
-				{"YUL", "JFK"},
-				{"JFK", "LHR"},
+				{Departure: "CNF", Arrival: "GRU"},
+				{Departure: "GRU", Arrival: "MIA"},
+				{Departure: "MIA", Arrival: "ORD"},
+				{Departure: "ORD", Arrival: "SFO"},
+				{Departure: "SFO", Arrival: "YUL"},
+				{Departure: "YUL", Arrival: "JFK"},
+				{Departure: "JFK", Arrival: "LHR"},
 			},
 		},
 		{
@@ -176,13 +179,13 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "CNF",
 			wantDestination: "LHR",
 			wantSortedLegs: []model.FlightLeg{
-				{"CNF", "GRU"},
-				{"GRU", "MIA"},
-				{"MIA", "ORD"},
-				{"ORD", "SFO"},
-				{"SFO", "YUL"},
-				{"YUL", "JFK"},
-				{"JFK", "LHR"},
+				{Departure: "CNF", Arrival: "GRU"},
+				{Departure: "GRU", Arrival: "MIA"},
+				{Departure: "MIA", Arrival: "ORD"},
+				{Departure: "ORD", Arrival: "SFO"},
+				{Departure: "SFO", Arrival: "YUL"},
+				{Departure: "YUL", Arrival: "JFK"},
+				{Departure: "JFK", Arrival: "LHR"},
 			},
 		},
 		{
@@ -220,13 +223,13 @@ func TestCalculateFlightPath_Success(t *testing.T) {
 			wantOrigin:      "CNF",
 			wantDestination: "LHR",
 			wantSortedLegs: []model.FlightLeg{
-				{"CNF", "GRU"},
-				{"GRU", "MIA"},
-				{"MIA", "ORD"},
-				{"ORD", "SFO"},
-				{"SFO", "YUL"},
-				{"YUL", "JFK"},
-				{"JFK", "LHR"},
+				{Departure: "CNF", Arrival: "GRU"},
+				{Departure: "GRU", Arrival: "MIA"},
+				{Departure: "MIA", Arrival: "ORD"},
+				{Departure: "ORD", Arrival: "SFO"},
+				{Departure: "SFO", Arrival: "YUL"},
+				{Departure: "YUL", Arrival: "JFK"},
+				{Departure: "JFK", Arrival: "LHR"},
 			},
 		},
 	}
@@ -265,7 +268,7 @@ func TestFindFlightPathStartEnd_Error(t *testing.T) {
 			wantError: `invalid flight path; invalid connection - "from" and "to" are the same`,
 		},
 		{
-			name: "when the flight path has an airport with more than one outbound legs (repeats)",
+			name: "when the flight path repeats flight legs",
 			flightLegs: []model.FlightLeg{
 				{
 					Departure: "CNF",
@@ -300,7 +303,7 @@ func TestFindFlightPathStartEnd_Error(t *testing.T) {
 					Arrival:   "LHR",
 				},
 			},
-			wantError: `invalid flight path; invalid connection - "from" already has an outbound connection`,
+			wantError: "invalid flight path; duplicate flight leg from ORD to SFO",
 		},
 		{
 			name: "when the flight path has an airport with more than one outbound legs (branch)",
@@ -584,3 +587,223 @@ func TestFindFlightPathStartEnd_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateFlightPathContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	flightPath, err := CalculateFlightPathContext(ctx, []model.FlightLeg{
+		{Departure: "ATL", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL"},
+	})
+
+	assert.Nil(t, flightPath)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "flight path calculation aborted; context canceled")
+}
+
+func TestCalculateFlightPathContext_DeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	flightPath, err := CalculateFlightPathContext(ctx, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+	})
+
+	assert.Nil(t, flightPath)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCalculateFlightPathContext_CanceledWhileSorting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := NewPath[model.AirportCode]()
+	assert.NoError(t, path.AddConnection("SFO", "ATL"))
+	assert.NoError(t, path.AddConnection("ATL", "EWR"))
+
+	cancel()
+
+	legsByDeparture := map[model.AirportCode]addedFlightLeg{
+		"SFO": {FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL"}, position: 0},
+		"ATL": {FlightLeg: model.FlightLeg{Departure: "ATL", Arrival: "EWR"}, position: 1},
+	}
+
+	sortedLegs, err := sortFlightLegs(ctx, path, legsByDeparture, "SFO", "EWR")
+	assert.Nil(t, sortedLegs)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCalculateFlightPath_PreservesFlightLegTimes(t *testing.T) {
+	firstDeparture := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	firstArrival := time.Date(2024, 3, 25, 13, 0, 0, 0, time.UTC)
+	secondDeparture := time.Date(2024, 3, 25, 15, 0, 0, 0, time.UTC)
+
+	flightLegs := []model.FlightLeg{
+		{Departure: "ATL", Arrival: "EWR", DepartureTime: &secondDeparture},
+		{Departure: "SFO", Arrival: "ATL", DepartureTime: &firstDeparture, ArrivalTime: &firstArrival},
+	}
+
+	got, err := CalculateFlightPath(flightLegs)
+	assert.NoError(t, err)
+	assert.Equal(t, got.FlightLegs, []model.FlightLeg{flightLegs[1], flightLegs[0]})
+}
+
+func TestFlightPathBuilder(t *testing.T) {
+	builder := NewFlightPathBuilder()
+
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "ATL", Arrival: "EWR"}))
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "ATL"}))
+	assert.Equal(t, builder.Length(), 2)
+
+	got, err := builder.Build(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, got, &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "EWR",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "EWR"},
+		},
+	})
+}
+
+func TestFlightPathBuilder_FailsFast(t *testing.T) {
+	builder := NewFlightPathBuilder()
+
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "ATL"}))
+
+	err := builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "EWR"})
+	assert.ErrorIs(t, err, ErrInvalidFlightPath)
+	assert.ErrorIs(t, err, ErrOutboundConnectionExists)
+	assert.Equal(t, builder.Length(), 1)
+}
+
+func TestCalculateFlightPathWithOptions_Duplicates(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	sameDeparture := departure.In(time.FixedZone("PDT", -7*60*60))
+
+	flightLegs := []model.FlightLeg{
+		{Departure: "ATL", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+		{Departure: "ATL", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL", DepartureTime: &sameDeparture},
+	}
+	want := []model.FlightLeg{flightLegs[1], flightLegs[0]}
+
+	t.Run("reject", func(t *testing.T) {
+		got, err := CalculateFlightPathWithOptions(
+			context.Background(), flightLegs, CalculationOptions{Duplicates: RejectDuplicates},
+		)
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, ErrInvalidFlightPath)
+		assert.ErrorIs(t, err, ErrDuplicateFlightLeg)
+		assert.EqualError(t, err, "invalid flight path; duplicate flight leg from ATL to EWR")
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		got, err := CalculateFlightPathWithOptions(
+			context.Background(), flightLegs, CalculationOptions{Duplicates: SkipDuplicates},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, got.FlightLegs, want)
+		assert.Empty(t, got.Warnings)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		got, err := CalculateFlightPathWithOptions(
+			context.Background(), flightLegs, CalculationOptions{Duplicates: WarnDuplicates},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, got.FlightLegs, want)
+
+		var warned []model.FlightLeg
+		for _, warning := range got.Warnings {
+			assert.ErrorIs(t, warning.Err, ErrDuplicateFlightLeg)
+			warned = append(warned, warning.FlightLeg)
+		}
+		assert.Equal(t, warned, []model.FlightLeg{flightLegs[2], flightLegs[3]})
+	})
+}
+
+func TestCalculateFlightPathWithOptions_SameAirportsAtDifferentTimesAreNotDuplicates(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	laterDeparture := departure.Add(24 * time.Hour)
+
+	for _, policy := range []DuplicatePolicy{RejectDuplicates, SkipDuplicates, WarnDuplicates} {
+		got, err := CalculateFlightPathWithOptions(context.Background(), []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &laterDeparture},
+		}, CalculationOptions{Duplicates: policy})
+
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, ErrOutboundConnectionExists)
+		assert.NotErrorIs(t, err, ErrDuplicateFlightLeg)
+	}
+}
+
+func TestFlightPathBuilder_Empty(t *testing.T) {
+	got, err := NewFlightPathBuilder().Build(context.Background())
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, ErrEmptyFlightPath)
+}
+
+func BenchmarkCalculateFlightPath(b *testing.B) {
+	g := generator.New(42)
+
+	benchmarks := []struct {
+		name      string
+		legs      []model.FlightLeg
+		wantError bool
+	}{
+		{"itinerary/10", g.Itinerary(10), false},
+		{"itinerary/1000", g.Itinerary(1_000), false},
+		{"itinerary/17575", g.Itinerary(generator.PlainAirportCodes - 1), false},
+		{"long chain/17575", g.LongChain(generator.PlainAirportCodes - 1), false},
+		{"long chain/100000", g.LongChain(100_000), false},
+		{"near loop/17575", g.NearLoop(generator.PlainAirportCodes - 1), true},
+		{"many branches/10000+1000", g.ManyBranches(10_000, 1_000), true},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_, err := CalculateFlightPath(bm.legs)
+				if (err != nil) != bm.wantError {
+					b.Fatalf("CalculateFlightPath() error = %v, wantError = %v", err, bm.wantError)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateFlightPath_DisconnectedPartitions(t *testing.T) {
+	// whichever start and end are found, at least one partition is left out of the walk
+	flightPath, err := CalculateFlightPath([]model.FlightLeg{
+		{Departure: "AAA", Arrival: "BBB"},
+		{Departure: "CCC", Arrival: "DDD"},
+	})
+
+	assert.Nil(t, flightPath)
+	assert.ErrorIs(t, err, ErrDisconnectedFlightPath)
+}
+
+func TestCalculateFlightPath_EmptyAirportCode(t *testing.T) {
+	// the domain doesn't validate airport codes, but an empty one must not be mistaken for a missing connection
+	flightPath, err := CalculateFlightPath([]model.FlightLeg{
+		{Departure: "", Arrival: "ATL"},
+		{Departure: "SFO", Arrival: ""},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, flightPath, &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "ATL",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: ""},
+			{Departure: "", Arrival: "ATL"},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const (
+	// maxGapChains is the largest number of partitions of a disconnected flight path for which missing flight legs
+	// are suggested. Beyond it, there are too many ways to connect them for any single suggestion to be meaningful.
+	maxGapChains = 32
+	// maxSuggestedFlightLegs is how many of the most plausible missing flight legs are suggested
+	maxSuggestedFlightLegs = 10
+)
+
+// GapRanker tells how plausible a flight leg from one airport to another is, such as according to a route network.
+type GapRanker interface {
+	// Plausibility goes from 0, for flight legs that are not plausible at all, to 1.
+	Plausibility(departure, arrival model.AirportCode) float64
+}
+
+// SuggestedFlightLeg is a flight leg that was probably left out of a disconnected flight path.
+type SuggestedFlightLeg struct {
+	Departure model.AirportCode
+	Arrival   model.AirportCode
+	// Plausibility goes from 0, exclusive, to 1
+	Plausibility float64
+}
+
+// DisconnectedFlightPathError is returned when the flight legs form more than one flight path. It suggests the
+// flight legs that would connect them, starting with the most plausible one.
+type DisconnectedFlightPathError struct {
+	err                 error
+	SuggestedFlightLegs []SuggestedFlightLeg
+}
+
+func (e *DisconnectedFlightPathError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DisconnectedFlightPathError) Unwrap() error {
+	return e.err
+}
+
+// flightLegChain is a partition of a disconnected flight path, which is a flight path of its own
+type flightLegChain struct {
+	first, last model.FlightLeg
+	// position is the earliest order in which any of its flight legs was added
+	position int
+}
+
+// suggestMissingFlightLegs finds every partition of a disconnected flight path, then suggests the flight legs that go
+// from the end of one partition to the start of another, such as ATL→GSO for SFO→ATL and GSO→IND. A suggestion is
+// only plausible if it departs after the partition before it arrives, and if the ranker, when given, finds it
+// plausible. Equally plausible suggestions are sorted by the order their flight legs were added in.
+//
+// Nothing is suggested if some of the flight legs form a loop, which no flight leg can connect to the others, or if
+// there are more than maxGapChains partitions.
+func suggestMissingFlightLegs(legs map[model.AirportCode]addedFlightLeg, ranker GapRanker) []SuggestedFlightLeg {
+	arrivals := make(map[model.AirportCode]bool, len(legs))
+	for _, leg := range legs {
+		arrivals[leg.Arrival] = true
+	}
+
+	var chains []flightLegChain
+	var chained int
+	for departure, leg := range legs {
+		if arrivals[departure] {
+			continue
+		}
+		if len(chains) == maxGapChains {
+			return nil
+		}
+
+		chain := flightLegChain{first: leg.FlightLeg, last: leg.FlightLeg, position: leg.position}
+		chained++
+		for next, ok := legs[leg.Arrival]; ok; next, ok = legs[next.Arrival] {
+			chain.last = next.FlightLeg
+			chain.position = min(chain.position, next.position)
+			chained++
+		}
+		chains = append(chains, chain)
+	}
+
+	if chained < len(legs) {
+		return nil
+	}
+
+	slices.SortFunc(chains, func(a, b flightLegChain) int {
+		return cmp.Compare(a.position, b.position)
+	})
+
+	var suggestions []SuggestedFlightLeg
+	for i, before := range chains {
+		for j, after := range chains {
+			if i == j {
+				continue
+			}
+
+			suggestion := SuggestedFlightLeg{
+				Departure:    before.last.Arrival,
+				Arrival:      after.first.Departure,
+				Plausibility: scheduledPlausibility(before.last, after.first),
+			}
+			if ranker != nil && suggestion.Plausibility > 0 {
+				suggestion.Plausibility *= ranker.Plausibility(suggestion.Departure, suggestion.Arrival)
+			}
+			if suggestion.Plausibility > 0 {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b SuggestedFlightLeg) int {
+		return cmp.Compare(b.Plausibility, a.Plausibility)
+	})
+	if len(suggestions) > maxSuggestedFlightLegs {
+		suggestions = suggestions[:maxSuggestedFlightLegs]
+	}
+	return suggestions
+}
+
+// scheduledPlausibility is 0 if a flight leg between the two flight legs would have to depart before the first one
+// arrives, or 1 otherwise, including when their times are unknown.
+func scheduledPlausibility(before, after model.FlightLeg) float64 {
+	landed := cmp.Or(before.ArrivalTime, before.DepartureTime)
+	departs := cmp.Or(after.DepartureTime, after.ArrivalTime)
+	if landed != nil && departs != nil && departs.Before(*landed) {
+		return 0
+	}
+	return 1
+}
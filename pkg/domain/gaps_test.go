@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// routeRanker finds the given routes plausible, and any other half as plausible
+type routeRanker map[string]bool
+
+func (r routeRanker) Plausibility(departure, arrival model.AirportCode) float64 {
+	if r[string(departure)+"-"+string(arrival)] {
+		return 1
+	}
+	return 0.5
+}
+
+func suggestedFlightLegsOf(t *testing.T, flightLegs []model.FlightLeg, ranker GapRanker) []SuggestedFlightLeg {
+	flightPath, err := CalculateFlightPathWithOptions(context.Background(), flightLegs,
+		CalculationOptions{GapRanker: ranker})
+	assert.Nil(t, flightPath)
+	assert.ErrorIs(t, err, ErrDisconnectedFlightPath)
+
+	var disconnected *DisconnectedFlightPathError
+	if !assert.ErrorAs(t, err, &disconnected) {
+		return nil
+	}
+	return disconnected.SuggestedFlightLegs
+}
+
+func TestSuggestMissingFlightLegs(t *testing.T) {
+	got := suggestedFlightLegsOf(t, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "GSO", Arrival: "IND"},
+	}, nil)
+
+	assert.Equal(t, got, []SuggestedFlightLeg{
+		{Departure: "ATL", Arrival: "GSO", Plausibility: 1},
+		{Departure: "IND", Arrival: "SFO", Plausibility: 1},
+	})
+}
+
+func TestSuggestMissingFlightLegs_OrderOfTheFlightLegs(t *testing.T) {
+	// the partition with the first flight leg is suggested to come first, even if its airports are added later
+	got := suggestedFlightLegsOf(t, []model.FlightLeg{
+		{Departure: "ATL", Arrival: "GSO"},
+		{Departure: "IND", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL"},
+	}, nil)
+
+	assert.Equal(t, got, []SuggestedFlightLeg{
+		{Departure: "GSO", Arrival: "IND", Plausibility: 1},
+		{Departure: "EWR", Arrival: "SFO", Plausibility: 1},
+	})
+}
+
+func TestSuggestMissingFlightLegs_Schedule(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	arrival := departure.Add(5 * time.Hour)
+	connection := arrival.Add(2 * time.Hour)
+
+	got := suggestedFlightLegsOf(t, []model.FlightLeg{
+		{Departure: "GSO", Arrival: "IND", DepartureTime: &connection},
+		{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure, ArrivalTime: &arrival},
+	}, nil)
+
+	// flying from IND back to SFO would have to depart before arriving at IND
+	assert.Equal(t, got, []SuggestedFlightLeg{
+		{Departure: "ATL", Arrival: "GSO", Plausibility: 1},
+	})
+}
+
+func TestSuggestMissingFlightLegs_GapRanker(t *testing.T) {
+	got := suggestedFlightLegsOf(t, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "GSO", Arrival: "IND"},
+		{Departure: "ORD", Arrival: "EWR"},
+	}, routeRanker{"IND-ORD": true, "EWR-SFO": true})
+
+	assert.Equal(t, got, []SuggestedFlightLeg{
+		{Departure: "IND", Arrival: "ORD", Plausibility: 1},
+		{Departure: "EWR", Arrival: "SFO", Plausibility: 1},
+		{Departure: "ATL", Arrival: "GSO", Plausibility: 0.5},
+		{Departure: "ATL", Arrival: "ORD", Plausibility: 0.5},
+		{Departure: "IND", Arrival: "SFO", Plausibility: 0.5},
+		{Departure: "EWR", Arrival: "GSO", Plausibility: 0.5},
+	})
+}
+
+func TestSuggestMissingFlightLegs_NothingToSuggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		flightLegs []model.FlightLeg
+	}{
+		{
+			name: "when some flight legs form a loop",
+			flightLegs: []model.FlightLeg{
+				{Departure: "SFO", Arrival: "ATL"},
+				{Departure: "GSO", Arrival: "IND"},
+				{Departure: "IND", Arrival: "GSO"},
+			},
+		},
+		{
+			name:       "when there are too many partitions",
+			flightLegs: disjointFlightLegs(maxGapChains + 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, suggestedFlightLegsOf(t, tt.flightLegs, nil))
+		})
+	}
+}
+
+func disjointFlightLegs(count int) []model.FlightLeg {
+	legs := make([]model.FlightLeg, count)
+	for i := range legs {
+		legs[i] = model.FlightLeg{
+			Departure: model.AirportCode(fmt.Sprintf("D%02d", i)),
+			Arrival:   model.AirportCode(fmt.Sprintf("A%02d", i)),
+		}
+	}
+	return legs
+}
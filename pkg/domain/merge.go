@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var ErrConflictingFlightLegs = errors.New("the fragments have conflicting flight legs")
+
+// Fragment is part of the flight legs of a traveler, as given by one source, such as the airline, the travel agency
+// or the corporate booking tool.
+type Fragment struct {
+	Source     string
+	FlightLegs []model.FlightLeg
+}
+
+// SourcedFlightLeg is a flight leg along with every source that gave it, in the order they were given.
+type SourcedFlightLeg struct {
+	model.FlightLeg
+	Sources []string
+}
+
+// FlightLegConflict is a contradiction between flight legs that cannot all be part of the same flight path.
+type FlightLegConflict struct {
+	Airport model.AirportCode
+	// Err is ErrOutboundConnectionExists if the flight legs depart from the airport, or ErrInboundConnectionExists
+	// if they arrive at it.
+	Err        error
+	FlightLegs []SourcedFlightLeg
+}
+
+// MergeConflictError has every conflict found while merging fragments, in the order their flight legs were given.
+type MergeConflictError struct {
+	Conflicts []FlightLegConflict
+}
+
+func (e *MergeConflictError) Error() string {
+	first := e.Conflicts[0]
+	return fmt.Sprintf("%v; %v conflicts, starting with %v flight legs at airport %v: %v",
+		ErrConflictingFlightLegs, len(e.Conflicts), len(first.FlightLegs), first.Airport, first.Err)
+}
+
+func (e *MergeConflictError) Unwrap() error {
+	return ErrConflictingFlightLegs
+}
+
+// airportPair identifies the flight legs between the same airports, in the same direction
+type airportPair struct {
+	departure, arrival model.AirportCode
+}
+
+// MergeFragments combines the flight legs of every fragment. The same flight leg, given by different sources or more
+// than once, is merged into one, along with its sources. Flight legs between the same airports are the same flight leg
+// if their departure and arrival times are the same, regardless of the time zones, or if one of them leaves a time
+// out, in which case the merged flight leg takes the time from the other one. If two different flight legs depart
+// from, or arrive at, the same airport, the sources contradict each other, and a *MergeConflictError is returned with
+// every such conflict.
+func MergeFragments(fragments []Fragment) ([]SourcedFlightLeg, error) {
+	var merged []SourcedFlightLeg
+	positions := make(map[airportPair][]int)
+
+	for _, fragment := range fragments {
+		for _, leg := range fragment.FlightLegs {
+			pair := airportPair{departure: leg.Departure, arrival: leg.Arrival}
+			k := slices.IndexFunc(positions[pair], func(i int) bool {
+				return sameFlightLegTime(merged[i].DepartureTime, leg.DepartureTime) &&
+					sameFlightLegTime(merged[i].ArrivalTime, leg.ArrivalTime)
+			})
+
+			var i int
+			if k < 0 {
+				i = len(merged)
+				positions[pair] = append(positions[pair], i)
+				merged = append(merged, SourcedFlightLeg{FlightLeg: leg})
+			} else {
+				i = positions[pair][k]
+				fillMissingTimes(&merged[i].FlightLeg, leg)
+			}
+
+			if !slices.Contains(merged[i].Sources, fragment.Source) {
+				merged[i].Sources = append(merged[i].Sources, fragment.Source)
+			}
+		}
+	}
+
+	if conflicts := findConflicts(merged); len(conflicts) > 0 {
+		return nil, &MergeConflictError{Conflicts: conflicts}
+	}
+	return merged, nil
+}
+
+// sameFlightLegTime tells if two times of the same kind, such as departure times, may belong to the same flight leg
+func sameFlightLegTime(a, b *time.Time) bool {
+	return a == nil || b == nil || a.Equal(*b)
+}
+
+func fillMissingTimes(leg *model.FlightLeg, other model.FlightLeg) {
+	if leg.DepartureTime == nil {
+		leg.DepartureTime = other.DepartureTime
+	}
+	if leg.ArrivalTime == nil {
+		leg.ArrivalTime = other.ArrivalTime
+	}
+}
+
+func findConflicts(legs []SourcedFlightLeg) []FlightLegConflict {
+	// the positions of the flight legs departing from, and arriving at, each airport
+	departing := make(map[model.AirportCode][]int)
+	arriving := make(map[model.AirportCode][]int)
+	for i, leg := range legs {
+		departing[leg.Departure] = append(departing[leg.Departure], i)
+		arriving[leg.Arrival] = append(arriving[leg.Arrival], i)
+	}
+
+	// each conflict is reported once, when its first flight leg is reached
+	var conflicts []FlightLegConflict
+	for i, leg := range legs {
+		if others := departing[leg.Departure]; len(others) > 1 && others[0] == i {
+			conflicts = append(conflicts, newFlightLegConflict(leg.Departure, ErrOutboundConnectionExists, legs, others))
+		}
+		if others := arriving[leg.Arrival]; len(others) > 1 && others[0] == i {
+			conflicts = append(conflicts, newFlightLegConflict(leg.Arrival, ErrInboundConnectionExists, legs, others))
+		}
+	}
+	return conflicts
+}
+
+func newFlightLegConflict(
+	airport model.AirportCode, err error, legs []SourcedFlightLeg, positions []int,
+) FlightLegConflict {
+	conflicting := make([]SourcedFlightLeg, len(positions))
+	for k, i := range positions {
+		conflicting[k] = legs[i]
+	}
+	return FlightLegConflict{Airport: airport, Err: err, FlightLegs: conflicting}
+}
+
+// CalculateMergedFlightPathContext merges the fragments, then calculates the flight path of the merged flight legs,
+// along with the sources of each one of them.
+func CalculateMergedFlightPathContext(ctx context.Context, fragments []Fragment) (*model.MergedFlightPath, error) {
+	merged, err := MergeFragments(fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]model.FlightLeg, len(merged))
+	sourcesByDeparture := make(map[model.AirportCode][]string, len(merged))
+	for i, leg := range merged {
+		legs[i] = leg.FlightLeg
+		sourcesByDeparture[leg.Departure] = leg.Sources
+	}
+
+	flightPath, err := CalculateFlightPathContext(ctx, legs)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([][]string, len(flightPath.FlightLegs))
+	for i, leg := range flightPath.FlightLegs {
+		sources[i] = sourcesByDeparture[leg.Departure]
+	}
+
+	return &model.MergedFlightPath{
+		FlightPath:       *flightPath,
+		FlightLegSources: sources,
+	}, nil
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestMergeFragments(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 15, 0, 0, 0, time.UTC)
+	sameDeparture := departure.In(time.FixedZone("PDT", -7*60*60))
+
+	merged, err := MergeFragments([]Fragment{
+		{Source: "airline", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+			{Departure: "ATL", Arrival: "GSO"},
+		}},
+		{Source: "agency", FlightLegs: []model.FlightLeg{
+			{Departure: "GSO", Arrival: "IND"},
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &sameDeparture},
+			{Departure: "GSO", Arrival: "IND"},
+		}},
+		{Source: "booking tool", FlightLegs: []model.FlightLeg{
+			{Departure: "ATL", Arrival: "GSO"},
+		}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, merged, []SourcedFlightLeg{
+		{
+			FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+			Sources:   []string{"airline", "agency"},
+		},
+		{
+			FlightLeg: model.FlightLeg{Departure: "ATL", Arrival: "GSO"},
+			Sources:   []string{"airline", "booking tool"},
+		},
+		{
+			FlightLeg: model.FlightLeg{Departure: "GSO", Arrival: "IND"},
+			Sources:   []string{"agency"},
+		},
+	})
+}
+
+func TestMergeFragments_MissingTimes(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC)
+	arrival := time.Date(2024, 3, 25, 15, 0, 0, 0, time.UTC)
+
+	merged, err := MergeFragments([]Fragment{
+		{Source: "airline", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+		}},
+		{Source: "agency", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+		}},
+		{Source: "booking tool", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", ArrivalTime: &arrival},
+		}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, merged, []SourcedFlightLeg{
+		{
+			FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure, ArrivalTime: &arrival},
+			Sources:   []string{"airline", "agency", "booking tool"},
+		},
+	})
+}
+
+func TestMergeFragments_Conflicts(t *testing.T) {
+	departure := time.Date(2024, 3, 25, 15, 0, 0, 0, time.UTC)
+	laterDeparture := departure.Add(time.Hour)
+
+	merged, err := MergeFragments([]Fragment{
+		{Source: "airline", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+			{Departure: "ATL", Arrival: "GSO"},
+			{Departure: "GSO", Arrival: "IND"},
+		}},
+		{Source: "agency", FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: &laterDeparture},
+			{Departure: "ORD", Arrival: "GSO"},
+		}},
+	})
+
+	assert.Nil(t, merged)
+	assert.ErrorIs(t, err, ErrConflictingFlightLegs)
+
+	var conflictError *MergeConflictError
+	assert.ErrorAs(t, err, &conflictError)
+	assert.Equal(t, conflictError.Conflicts, []FlightLegConflict{
+		{
+			Airport: "SFO",
+			Err:     ErrOutboundConnectionExists,
+			FlightLegs: []SourcedFlightLeg{
+				{
+					FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+					Sources:   []string{"airline"},
+				},
+				{
+					FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &laterDeparture},
+					Sources:   []string{"agency"},
+				},
+			},
+		},
+		{
+			Airport: "ATL",
+			Err:     ErrInboundConnectionExists,
+			FlightLegs: []SourcedFlightLeg{
+				{
+					FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &departure},
+					Sources:   []string{"airline"},
+				},
+				{
+					FlightLeg: model.FlightLeg{Departure: "SFO", Arrival: "ATL", DepartureTime: &laterDeparture},
+					Sources:   []string{"agency"},
+				},
+			},
+		},
+		{
+			Airport: "GSO",
+			Err:     ErrInboundConnectionExists,
+			FlightLegs: []SourcedFlightLeg{
+				{FlightLeg: model.FlightLeg{Departure: "ATL", Arrival: "GSO"}, Sources: []string{"airline"}},
+				{FlightLeg: model.FlightLeg{Departure: "ORD", Arrival: "GSO"}, Sources: []string{"agency"}},
+			},
+		},
+	})
+	assert.EqualError(t, err, "the fragments have conflicting flight legs; 3 conflicts, starting with 2 flight legs "+
+		`at airport SFO: invalid connection - "from" already has an outbound connection`)
+}
+
+func TestCalculateMergedFlightPathContext(t *testing.T) {
+	flightPath, err := CalculateMergedFlightPathContext(context.Background(), []Fragment{
+		{Source: "airline", FlightLegs: []model.FlightLeg{
+			{Departure: "ATL", Arrival: "GSO"},
+			{Departure: "GSO", Arrival: "IND"},
+		}},
+		{Source: "agency", FlightLegs: []model.FlightLeg{
+			{Departure: "GSO", Arrival: "IND"},
+			{Departure: "SFO", Arrival: "ATL"},
+		}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, flightPath, &model.MergedFlightPath{
+		FlightPath: model.FlightPath{
+			Origin:      "SFO",
+			Destination: "IND",
+			FlightLegs: []model.FlightLeg{
+				{Departure: "SFO", Arrival: "ATL"},
+				{Departure: "ATL", Arrival: "GSO"},
+				{Departure: "GSO", Arrival: "IND"},
+			},
+		},
+		FlightLegSources: [][]string{{"agency"}, {"airline"}, {"airline", "agency"}},
+	})
+}
+
+func TestCalculateMergedFlightPathContext_InvalidFlightPath(t *testing.T) {
+	// the fragments agree with each other, but their flight legs form a loop
+	flightPath, err := CalculateMergedFlightPathContext(context.Background(), []Fragment{
+		{Source: "airline", FlightLegs: []model.FlightLeg{{Departure: "SFO", Arrival: "ATL"}}},
+		{Source: "agency", FlightLegs: []model.FlightLeg{{Departure: "ATL", Arrival: "SFO"}}},
+	})
+
+	assert.Nil(t, flightPath)
+	assert.ErrorIs(t, err, ErrStartNotFound)
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// packedAirportCodes is the number of plain IATA airport codes, from AAA to ZZZ
+const packedAirportCodes = 26 * 26 * 26
+
+var errUnpackableAirportCode = errors.New("airport code is not made of three uppercase letters")
+
+// packedAirportCode is a plain IATA airport code, made of three uppercase letters, packed into a number from 0 to
+// 26³-1. It fits in a uint16, and can index arrays instead of being hashed into maps.
+type packedAirportCode uint16
+
+func packAirportCode(code model.AirportCode) (packedAirportCode, bool) {
+	if len(code) != 3 {
+		return 0, false
+	}
+
+	var packed packedAirportCode
+	for i := 0; i < 3; i++ {
+		letter := code[i]
+		if letter < 'A' || letter > 'Z' {
+			return 0, false
+		}
+		packed = packed*26 + packedAirportCode(letter-'A')
+	}
+	return packed, true
+}
+
+// unpackedAirportCodes has every plain IATA airport code, in order, one after the other: "AAAAABAAC...ZZZ". Unpacking
+// a code slices this string, which doesn't allocate memory.
+var unpackedAirportCodes = func() string {
+	var codes strings.Builder
+	codes.Grow(3 * packedAirportCodes)
+	for first := 'A'; first <= 'Z'; first++ {
+		for second := 'A'; second <= 'Z'; second++ {
+			for third := 'A'; third <= 'Z'; third++ {
+				codes.WriteRune(first)
+				codes.WriteRune(second)
+				codes.WriteRune(third)
+			}
+		}
+	}
+	return codes.String()
+}()
+
+func (code packedAirportCode) unpack() model.AirportCode {
+	return model.AirportCode(unpackedAirportCodes[3*int(code) : 3*int(code)+3])
+}
+
+// airportPath is what FlightPathBuilder needs from a path of airports. It is implemented both by the generic
+// Path[model.AirportCode] and by packedPath.
+type airportPath interface {
+	AddConnection(from, to model.AirportCode) error
+	FindStart() (model.AirportCode, error)
+	FindEnd() (model.AirportCode, error)
+	Next(a model.AirportCode) (model.AirportCode, bool)
+	Length() int
+}
+
+// packedPath is a Path of plain IATA airport codes. Instead of three maps, it is backed by two arrays indexed by
+// packed airport codes, which makes adding and following connections much faster, without any memory allocation.
+//
+// It rejects airport codes that cannot be packed with errUnpackableAirportCode, in which case the generic Path must
+// be used instead.
+type packedPath struct {
+	// outboundOf and inboundOf hold packed airport codes plus one, so that zero means there's no connection
+	outboundOf [packedAirportCodes]uint16
+	inboundOf  [packedAirportCodes]uint16
+
+	// points are kept in the order they were added, so that finding the start or the end of the path does not need
+	// to scan the arrays, and so that the arrays can be cleared quickly.
+	points []packedAirportCode
+	length int
+}
+
+// packedPaths recycles packed paths, since their arrays are too large to be allocated for every flight path
+var packedPaths = sync.Pool{
+	New: func() any {
+		return new(packedPath)
+	},
+}
+
+func newPackedPath() *packedPath {
+	return packedPaths.Get().(*packedPath)
+}
+
+// release clears the path and returns it to the pool. The path must not be used afterwards.
+func (p *packedPath) release() {
+	for _, point := range p.points {
+		p.outboundOf[point] = 0
+		p.inboundOf[point] = 0
+	}
+	p.points = p.points[:0]
+	p.length = 0
+	packedPaths.Put(p)
+}
+
+func (p *packedPath) AddConnection(from, to model.AirportCode) error {
+	packedFrom, ok := packAirportCode(from)
+	if !ok {
+		return errUnpackableAirportCode
+	}
+	packedTo, ok := packAirportCode(to)
+	if !ok {
+		return errUnpackableAirportCode
+	}
+
+	if packedFrom == packedTo {
+		return ErrSamePoints
+	}
+
+	if p.outboundOf[packedFrom] != 0 {
+		return ErrOutboundConnectionExists
+	}
+
+	if p.inboundOf[packedTo] != 0 {
+		return ErrInboundConnectionExists
+	}
+
+	if p.inboundOf[packedFrom] == 0 {
+		p.points = append(p.points, packedFrom)
+	}
+	if p.outboundOf[packedTo] == 0 {
+		p.points = append(p.points, packedTo)
+	}
+
+	p.outboundOf[packedFrom] = uint16(packedTo) + 1
+	p.inboundOf[packedTo] = uint16(packedFrom) + 1
+	p.length++
+
+	return nil
+}
+
+func (p *packedPath) FindStart() (model.AirportCode, error) {
+	for _, point := range p.points {
+		if p.inboundOf[point] == 0 {
+			return point.unpack(), nil
+		}
+	}
+	return "", ErrStartNotFound
+}
+
+func (p *packedPath) FindEnd() (model.AirportCode, error) {
+	for _, point := range p.points {
+		if p.outboundOf[point] == 0 {
+			return point.unpack(), nil
+		}
+	}
+	return "", ErrEndNotFound
+}
+
+func (p *packedPath) Next(a model.AirportCode) (model.AirportCode, bool) {
+	packed, ok := packAirportCode(a)
+	if !ok || p.outboundOf[packed] == 0 {
+		return "", false
+	}
+	return packedAirportCode(p.outboundOf[packed] - 1).unpack(), true
+}
+
+// Length is the number of connections in this path, just like Path.Length.
+func (p *packedPath) Length() int {
+	return p.length
+}
+
+// unpack copies the connections of this path into a generic Path, which accepts any airport code.
+func (p *packedPath) unpack() *Path[model.AirportCode] {
+	path := NewPath[model.AirportCode]()
+	for _, point := range p.points {
+		if next := p.outboundOf[point]; next != 0 {
+			// the connections were already validated, so this cannot fail
+			_ = path.AddConnection(point.unpack(), packedAirportCode(next-1).unpack())
+		}
+	}
+	return path
+}
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestPackAirportCode(t *testing.T) {
+	tests := []struct {
+		code       model.AirportCode
+		wantPacked packedAirportCode
+	}{
+		{"AAA", 0},
+		{"AAB", 1},
+		{"ABA", 26},
+		{"SFO", 18*26*26 + 5*26 + 14},
+		{"ZZZ", packedAirportCodes - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			packed, ok := packAirportCode(tt.code)
+			assert.True(t, ok)
+			assert.Equal(t, packed, tt.wantPacked)
+			assert.Equal(t, packed.unpack(), tt.code)
+		})
+	}
+}
+
+func TestPackAirportCode_Unpackable(t *testing.T) {
+	for _, code := range []model.AirportCode{"", "SF", "SFOO", "sfo", "SF0", "S-O", "ÀB"} {
+		t.Run(string(code), func(t *testing.T) {
+			_, ok := packAirportCode(code)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestPackedAirportCode_UnpackDoesNotAllocate(t *testing.T) {
+	packed, _ := packAirportCode("SFO")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = packed.unpack()
+	})
+	assert.Equal(t, allocs, float64(0))
+}
+
+func TestPackedPath(t *testing.T) {
+	p := newPackedPath()
+	defer p.release()
+
+	assert.NoError(t, p.AddConnection("IND", "EWR"))
+	assert.NoError(t, p.AddConnection("SFO", "ATL"))
+	assert.NoError(t, p.AddConnection("GSO", "IND"))
+	assert.NoError(t, p.AddConnection("ATL", "GSO"))
+
+	start, err := p.FindStart()
+	assert.NoError(t, err)
+	assert.Equal(t, start, model.AirportCode("SFO"))
+
+	end, err := p.FindEnd()
+	assert.NoError(t, err)
+	assert.Equal(t, end, model.AirportCode("EWR"))
+
+	next, ok := p.Next("ATL")
+	assert.True(t, ok)
+	assert.Equal(t, next, model.AirportCode("GSO"))
+	_, ok = p.Next("EWR")
+	assert.False(t, ok)
+	_, ok = p.Next("JFK")
+	assert.False(t, ok)
+	assert.Equal(t, p.Length(), 4)
+}
+
+func TestPackedPath_AddConnection_Errors(t *testing.T) {
+	p := newPackedPath()
+	defer p.release()
+
+	assert.NoError(t, p.AddConnection("SFO", "ATL"))
+
+	assert.ErrorIs(t, p.AddConnection("EWR", "EWR"), ErrSamePoints)
+	assert.ErrorIs(t, p.AddConnection("SFO", "EWR"), ErrOutboundConnectionExists)
+	assert.ErrorIs(t, p.AddConnection("EWR", "ATL"), ErrInboundConnectionExists)
+	assert.ErrorIs(t, p.AddConnection("ATL", "ewr"), errUnpackableAirportCode)
+	assert.Equal(t, p.Length(), 1)
+}
+
+func TestPackedPath_Loop(t *testing.T) {
+	p := newPackedPath()
+	defer p.release()
+
+	assert.NoError(t, p.AddConnection("SFO", "ATL"))
+	assert.NoError(t, p.AddConnection("ATL", "SFO"))
+
+	_, err := p.FindStart()
+	assert.ErrorIs(t, err, ErrStartNotFound)
+
+	_, err = p.FindEnd()
+	assert.ErrorIs(t, err, ErrEndNotFound)
+}
+
+func TestPackedPath_Release(t *testing.T) {
+	p := newPackedPath()
+	assert.NoError(t, p.AddConnection("SFO", "ATL"))
+	p.release()
+
+	assert.Equal(t, *p, packedPath{points: p.points})
+	assert.Empty(t, p.points)
+}
+
+func TestPackedPath_Unpack(t *testing.T) {
+	p := newPackedPath()
+	defer p.release()
+
+	assert.NoError(t, p.AddConnection("ATL", "EWR"))
+	assert.NoError(t, p.AddConnection("SFO", "ATL"))
+
+	path := p.unpack()
+
+	assert.Equal(t, path.GetNext("SFO"), model.AirportCode("ATL"))
+	assert.Equal(t, path.GetNext("ATL"), model.AirportCode("EWR"))
+	assert.Equal(t, path.Length(), 2)
+}
+
+func TestFlightPathBuilder_UsesPackedPath(t *testing.T) {
+	builder := NewFlightPathBuilder()
+	defer builder.Release()
+
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "ATL"}))
+
+	assert.IsType(t, builder.path, &packedPath{})
+}
+
+func TestFlightPathBuilder_FallsBackToGenericPath(t *testing.T) {
+	builder := NewFlightPathBuilder()
+
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "ATL"}))
+	assert.NoError(t, builder.AddFlightLeg(model.FlightLeg{Departure: "ATL", Arrival: "ewr"}))
+	assert.IsType(t, builder.path, &Path[model.AirportCode]{})
+
+	// the flight legs added before the fallback are still there
+	err := builder.AddFlightLeg(model.FlightLeg{Departure: "SFO", Arrival: "IND"})
+	assert.ErrorIs(t, err, ErrOutboundConnectionExists)
+
+	got, err := builder.Build(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, got, &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "ewr",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "ewr"},
+		},
+	})
+}
+
+func benchmarkAirportPath(b *testing.B, length int, newPath func() airportPath, release func(airportPath)) {
+	legs := generator.New(42).Itinerary(length)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		path := newPath()
+		for _, leg := range legs {
+			if err := path.AddConnection(leg.Departure, leg.Arrival); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		start, _ := path.FindStart()
+		end, _ := path.FindEnd()
+		for this := start; this != end; {
+			this, _ = path.Next(this)
+		}
+
+		release(path)
+	}
+}
+
+// BenchmarkAirportPath compares the generic map-based Path against packedPath, on paths made of plain IATA codes.
+func BenchmarkAirportPath(b *testing.B) {
+	for _, length := range []int{10, 1_000, generator.PlainAirportCodes - 1} {
+		b.Run(fmt.Sprintf("generic/%v", length), func(b *testing.B) {
+			benchmarkAirportPath(b, length,
+				func() airportPath { return NewPath[model.AirportCode]() },
+				func(airportPath) {})
+		})
+		b.Run(fmt.Sprintf("packed/%v", length), func(b *testing.B) {
+			benchmarkAirportPath(b, length,
+				func() airportPath { return newPackedPath() },
+				func(path airportPath) { path.(*packedPath).release() })
+		})
+	}
+}
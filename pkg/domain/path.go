@@ -2,78 +2,256 @@ package domain
 
 import (
 	"errors"
-	"math"
+	"iter"
 )
 
-// Path is a subset of Digraph, where for each vertex, or point, there can only exist at most one inbound edge,
-// or connection; and at most one outbound connection.
+var (
+	ErrSamePoints               = errors.New(`invalid connection - "from" and "to" are the same`)
+	ErrOutboundConnectionExists = errors.New(`invalid connection - "from" already has an outbound connection`)
+	ErrInboundConnectionExists  = errors.New(`invalid connection - "to" already has an inbound connection`)
+	ErrStartNotFound            = errors.New("unable to find start of path - there's a loop")
+	ErrEndNotFound              = errors.New("unable to find end of path - there's a loop")
+	ErrPointNotFound            = errors.New("point not found in path")
+	ErrInvalidSubPath           = errors.New(`invalid sub-path - "to" does not come after "from"`)
+	ErrConnectionNotFound       = errors.New("connection not found in path")
+)
+
+// Path is a constrained view over a Digraph, where for each vertex, or point, there can only exist at most one
+// inbound edge, or connection; and at most one outbound connection.
 //
 // All points in the path must be connected to form one path, i.e., there should exist no partitions. Also, there
 // should exist no cycles or branches in the path.
+//
+// A point is in the path as long as it has a connection, so any value of T can be a point, including its zero value.
 type Path[T comparable] struct {
-	points     map[T]bool
-	outboundOf map[T]T
-	inboundOf  map[T]T
+	graph *Digraph[T]
 }
 
 func NewPath[T comparable]() *Path[T] {
 	return &Path[T]{
-		points:     make(map[T]bool),
-		outboundOf: make(map[T]T),
-		inboundOf:  make(map[T]T),
+		graph: NewDigraph[T](),
 	}
 }
 
 func (p *Path[T]) AddConnection(from, to T) error {
 	if from == to {
-		return errors.New(`invalid connection - "from" and "to" are the same`)
+		return ErrSamePoints
 	}
 
-	var nullValue T
+	if p.graph.OutDegree(from) > 0 {
+		return ErrOutboundConnectionExists
+	}
+
+	if p.graph.InDegree(to) > 0 {
+		return ErrInboundConnectionExists
+	}
+
+	p.graph.AddConnection(from, to)
+
+	return nil
+}
+
+// RemoveConnection removes the connection from "from" to "to". Points left without any connection are removed from
+// the path as well.
+func (p *Path[T]) RemoveConnection(from, to T) error {
+	if !p.graph.RemoveConnection(from, to) {
+		return ErrConnectionNotFound
+	}
+
+	p.removeIfDisconnected(from)
+	p.removeIfDisconnected(to)
+
+	return nil
+}
+
+// ReplaceConnection replaces the connection from "from" to "to" with a connection from "newFrom" to "newTo". The
+// path is left unchanged if the new connection is not valid.
+func (p *Path[T]) ReplaceConnection(from, to, newFrom, newTo T) error {
+	if p.graph.Connections(from, to) == 0 {
+		return ErrConnectionNotFound
+	}
 
-	if outbound := p.outboundOf[from]; outbound != nullValue {
-		return errors.New(`invalid connection - "from" already has an outbound connection`)
+	if newFrom == newTo {
+		return ErrSamePoints
 	}
 
-	if p.inboundOf[to] != nullValue {
-		return errors.New(`invalid connection - "to" already has an inbound connection`)
+	// the connection being replaced doesn't count
+	if p.graph.OutDegree(newFrom) > 0 && newFrom != from {
+		return ErrOutboundConnectionExists
 	}
 
-	p.points[from] = true
-	p.points[to] = true
+	if p.graph.InDegree(newTo) > 0 && newTo != to {
+		return ErrInboundConnectionExists
+	}
 
-	p.outboundOf[from] = to
-	p.inboundOf[to] = from
+	p.graph.RemoveConnection(from, to)
+	p.graph.AddConnection(newFrom, newTo)
+	p.removeIfDisconnected(from)
+	p.removeIfDisconnected(to)
 
 	return nil
 }
 
+func (p *Path[T]) removeIfDisconnected(a T) {
+	if p.graph.InDegree(a) == 0 && p.graph.OutDegree(a) == 0 {
+		p.graph.RemovePoint(a)
+	}
+}
+
 func (p *Path[T]) FindStart() (T, error) {
+	for i, point := range p.graph.positions() {
+		if len(p.graph.inbound[i]) == 0 {
+			return point, nil
+		}
+	}
+
 	var nullValue T
+	return nullValue, ErrStartNotFound
+}
 
-	for i := range p.points {
-		if p.inboundOf[i] == nullValue {
-			return i, nil
+func (p *Path[T]) FindEnd() (T, error) {
+	for i, point := range p.graph.positions() {
+		if len(p.graph.outbound[i]) == 0 {
+			return point, nil
 		}
 	}
 
-	return nullValue, errors.New("unable to find start of path - there's a loop")
+	var nullValue T
+	return nullValue, ErrEndNotFound
 }
 
-func (p *Path[T]) FindEnd() (T, error) {
+// GetNext is the point "a" is connected to, or the zero value of T if there's none. Use Next to tell the zero value
+// apart from no connection.
+func (p *Path[T]) GetNext(a T) T {
+	next, _ := p.Next(a)
+	return next
+}
+
+// GetPrevious is the point connected to "a", or the zero value of T if there's none. Use Previous to tell the zero
+// value apart from no connection.
+func (p *Path[T]) GetPrevious(a T) T {
+	previous, _ := p.Previous(a)
+	return previous
+}
+
+// Next is the point "a" is connected to, or false if it has no outbound connection.
+func (p *Path[T]) Next(a T) (T, bool) {
+	var nullValue T
+
+	i, ok := p.graph.indexOf[a]
+	if !ok || len(p.graph.outbound[i]) == 0 {
+		return nullValue, false
+	}
+	return p.graph.points[p.graph.outbound[i][0]], true
+}
+
+// Previous is the point connected to "a", or false if it has no inbound connection.
+func (p *Path[T]) Previous(a T) (T, bool) {
 	var nullValue T
 
-	for i := range p.points {
-		if p.outboundOf[i] == nullValue {
-			return i, nil
+	i, ok := p.graph.indexOf[a]
+	if !ok || len(p.graph.inbound[i]) == 0 {
+		return nullValue, false
+	}
+	return p.graph.points[p.graph.inbound[i][0]], true
+}
+
+func (p *Path[T]) Contains(a T) bool {
+	return p.graph.HasPoint(a)
+}
+
+// Points walks the path from its start to its end. Nothing is walked if the path has a loop instead of a start, and
+// only the partition of the first start is walked if the path has more than one.
+func (p *Path[T]) Points() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		start, err := p.FindStart()
+		if err != nil {
+			return
+		}
+
+		for a, ok := start, true; ok; a, ok = p.Next(a) {
+			if !yield(a) {
+				return
+			}
 		}
 	}
+}
 
-	return nullValue, errors.New("unable to find end of path - there's a loop")
+// Connections walks the connections of the path from its start to its end, as Points does.
+func (p *Path[T]) Connections() iter.Seq2[T, T] {
+	return func(yield func(from, to T) bool) {
+		for from := range p.Points() {
+			to, ok := p.Next(from)
+			if !ok || !yield(from, to) {
+				return
+			}
+		}
+	}
 }
 
-func (p *Path[T]) GetNext(a T) T {
-	return p.outboundOf[a]
+// IndexOf is the position of "a" as the path is walked from its start, which is 0, or -1 if "a" is not walked.
+func (p *Path[T]) IndexOf(a T) int {
+	i := 0
+	for point := range p.Points() {
+		if point == a {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// SubPath copies the connections from "from" up to "to" into a new path. If "from" and "to" are the same, there are
+// no connections between them, so the new path is empty.
+func (p *Path[T]) SubPath(from, to T) (*Path[T], error) {
+	if !p.Contains(from) || !p.Contains(to) {
+		return nil, ErrPointNotFound
+	}
+
+	sub := NewPath[T]()
+
+	for a := from; a != to; {
+		next, ok := p.Next(a)
+		// "from" can only be reached again if there's a loop that doesn't go through "to"
+		if !ok || next == from {
+			return nil, ErrInvalidSubPath
+		}
+		sub.graph.AddConnection(a, next)
+		a = next
+	}
+
+	return sub, nil
+}
+
+// Reverse copies the path into a new one, where every connection goes the opposite way.
+func (p *Path[T]) Reverse() *Path[T] {
+	reversed := NewPath[T]()
+	for i, from := range p.graph.positions() {
+		reversed.graph.AddPoint(from)
+		for _, j := range p.graph.outbound[i] {
+			reversed.graph.AddConnection(p.graph.points[j], from)
+		}
+	}
+	return reversed
+}
+
+// Equal tells whether both paths have the same points, connected the same way, no matter the order they were added.
+func (p *Path[T]) Equal(other *Path[T]) bool {
+	if p.graph.Order() != other.graph.Order() || p.Length() != other.Length() {
+		return false
+	}
+
+	for _, a := range p.graph.positions() {
+		if !other.Contains(a) {
+			return false
+		}
+		next, ok := p.Next(a)
+		otherNext, otherOk := other.Next(a)
+		if ok != otherOk || next != otherNext {
+			return false
+		}
+	}
+	return true
 }
 
 // Length is the number of connections in this path. For example, given the path:
@@ -86,8 +264,5 @@ func (p *Path[T]) GetNext(a T) T {
 //
 // The length is going to be 5.
 func (p *Path[T]) Length() int {
-	return int(math.Max(
-		float64(len(p.inboundOf)),
-		float64(len(p.outboundOf)),
-	))
+	return p.graph.Size()
 }
@@ -1,9 +1,14 @@
 package domain
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
 )
 
 func TestPath_AddConnection_FailsIfPointsAreTheSame(t *testing.T) {
@@ -137,3 +142,348 @@ func TestPath_LengthWhenEmpty(t *testing.T) {
 
 	assert.Equal(t, p.Length(), 0)
 }
+
+// newTestPath adds the connections of a → b → c → d → e in a scrambled order
+func newTestPath(t *testing.T) *Path[string] {
+	p := NewPath[string]()
+
+	assert.NoError(t, p.AddConnection("c", "d"))
+	assert.NoError(t, p.AddConnection("a", "b"))
+	assert.NoError(t, p.AddConnection("d", "e"))
+	assert.NoError(t, p.AddConnection("b", "c"))
+
+	return p
+}
+
+func TestPath_GetPrevious(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.Equal(t, p.GetPrevious("e"), "d")
+	assert.Equal(t, p.GetPrevious("b"), "a")
+	assert.Equal(t, p.GetPrevious("a"), "")
+	assert.Equal(t, p.GetPrevious("z"), "")
+}
+
+func TestPath_Contains(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.True(t, p.Contains("a"))
+	assert.True(t, p.Contains("e"))
+	assert.False(t, p.Contains("z"))
+}
+
+func TestPath_Points(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.Equal(t, slices.Collect(p.Points()), []string{"a", "b", "c", "d", "e"})
+}
+
+func TestPath_Points_StopsWhenTheCallerStops(t *testing.T) {
+	p := newTestPath(t)
+
+	var points []string
+	for point := range p.Points() {
+		if point == "c" {
+			break
+		}
+		points = append(points, point)
+	}
+
+	assert.Equal(t, points, []string{"a", "b"})
+}
+
+func TestPath_Points_WalksNothingIfTheresALoop(t *testing.T) {
+	p := NewPath[string]()
+
+	assert.NoError(t, p.AddConnection("a", "b"))
+	assert.NoError(t, p.AddConnection("b", "a"))
+
+	assert.Empty(t, slices.Collect(p.Points()))
+	assert.Empty(t, slices.Collect(NewPath[string]().Points()))
+}
+
+func TestPath_Connections(t *testing.T) {
+	p := newTestPath(t)
+
+	var connections [][2]string
+	for from, to := range p.Connections() {
+		connections = append(connections, [2]string{from, to})
+	}
+
+	assert.Equal(t, connections, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}})
+}
+
+func TestPath_IndexOf(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.Equal(t, p.IndexOf("a"), 0)
+	assert.Equal(t, p.IndexOf("d"), 3)
+	assert.Equal(t, p.IndexOf("z"), -1)
+}
+
+func TestPath_SubPath(t *testing.T) {
+	p := newTestPath(t)
+
+	tests := []struct {
+		from, to string
+		want     []string
+		err      error
+	}{
+		{from: "a", to: "e", want: []string{"a", "b", "c", "d", "e"}},
+		{from: "b", to: "d", want: []string{"b", "c", "d"}},
+		{from: "d", to: "b", err: ErrInvalidSubPath},
+		{from: "a", to: "z", err: ErrPointNotFound},
+		{from: "z", to: "a", err: ErrPointNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+"-"+test.to, func(t *testing.T) {
+			sub, err := p.SubPath(test.from, test.to)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err != nil {
+				assert.Nil(t, sub)
+				return
+			}
+			assert.Equal(t, slices.Collect(sub.Points()), test.want)
+			assert.Equal(t, sub.Length(), len(test.want)-1)
+		})
+	}
+}
+
+func TestPath_SubPath_SamePoint(t *testing.T) {
+	p := newTestPath(t)
+
+	sub, err := p.SubPath("c", "c")
+
+	assert.NoError(t, err)
+	assert.Equal(t, sub.Length(), 0)
+	assert.Empty(t, slices.Collect(sub.Points()))
+	assert.False(t, sub.Contains("c"))
+	assert.True(t, sub.Equal(NewPath[string]()))
+}
+
+func TestPath_SubPath_FailsIfTheresALoop(t *testing.T) {
+	p := NewPath[string]()
+
+	assert.NoError(t, p.AddConnection("a", "b"))
+	assert.NoError(t, p.AddConnection("b", "a"))
+	assert.NoError(t, p.AddConnection("c", "d"))
+
+	sub, err := p.SubPath("b", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, sub.Length(), 1)
+
+	_, err = p.SubPath("a", "d")
+	assert.ErrorIs(t, err, ErrInvalidSubPath)
+}
+
+func TestPath_Reverse(t *testing.T) {
+	p := newTestPath(t)
+
+	reversed := p.Reverse()
+
+	assert.Equal(t, slices.Collect(reversed.Points()), []string{"e", "d", "c", "b", "a"})
+	assert.Equal(t, reversed.Length(), p.Length())
+	assert.True(t, reversed.Reverse().Equal(p))
+}
+
+func TestPath_Equal(t *testing.T) {
+	p := newTestPath(t)
+
+	same := NewPath[string]()
+	assert.NoError(t, same.AddConnection("a", "b"))
+	assert.NoError(t, same.AddConnection("b", "c"))
+	assert.NoError(t, same.AddConnection("c", "d"))
+	assert.NoError(t, same.AddConnection("d", "e"))
+
+	different := NewPath[string]()
+	assert.NoError(t, different.AddConnection("a", "b"))
+	assert.NoError(t, different.AddConnection("b", "d"))
+	assert.NoError(t, different.AddConnection("d", "c"))
+	assert.NoError(t, different.AddConnection("c", "e"))
+
+	shorter, err := p.SubPath("a", "d")
+	assert.NoError(t, err)
+
+	assert.True(t, p.Equal(p))
+	assert.True(t, p.Equal(same))
+	assert.True(t, same.Equal(p))
+	assert.False(t, p.Equal(different))
+	assert.False(t, p.Equal(shorter))
+	assert.False(t, shorter.Equal(p))
+	assert.True(t, NewPath[string]().Equal(NewPath[string]()))
+}
+
+func TestPath_ZeroValueIsAPoint(t *testing.T) {
+	p := NewPath[int]()
+
+	assert.NoError(t, p.AddConnection(1, 2))
+	assert.NoError(t, p.AddConnection(0, 1))
+	assert.NoError(t, p.AddConnection(2, 3))
+
+	// 0 already has an outbound connection, and a connection to 0 is not mistaken for no connection at all
+	assert.ErrorIs(t, p.AddConnection(0, 4), ErrOutboundConnectionExists)
+
+	start, err := p.FindStart()
+	assert.NoError(t, err)
+	assert.Equal(t, start, 0)
+	assert.True(t, p.Contains(0))
+	assert.Equal(t, slices.Collect(p.Points()), []int{0, 1, 2, 3})
+
+	previous, ok := p.Previous(1)
+	assert.True(t, ok)
+	assert.Equal(t, previous, 0)
+
+	_, ok = p.Previous(0)
+	assert.False(t, ok)
+}
+
+func TestPath_ZeroValueCanBeTheEnd(t *testing.T) {
+	p := NewPath[string]()
+
+	assert.NoError(t, p.AddConnection("a", ""))
+
+	end, err := p.FindEnd()
+	assert.NoError(t, err)
+	assert.Equal(t, end, "")
+	assert.ErrorIs(t, p.AddConnection("b", ""), ErrInboundConnectionExists)
+
+	next, ok := p.Next("a")
+	assert.True(t, ok)
+	assert.Equal(t, next, "")
+	_, ok = p.Next("")
+	assert.False(t, ok)
+	assert.Equal(t, p.Length(), 1)
+}
+
+func TestPath_RemoveConnection(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.NoError(t, p.RemoveConnection("d", "e"))
+
+	assert.Equal(t, slices.Collect(p.Points()), []string{"a", "b", "c", "d"})
+	assert.Equal(t, p.Length(), 3)
+	assert.False(t, p.Contains("e"))
+
+	assert.NoError(t, p.AddConnection("d", "f"))
+	assert.Equal(t, slices.Collect(p.Points()), []string{"a", "b", "c", "d", "f"})
+}
+
+func TestPath_RemoveConnection_InTheMiddle(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.NoError(t, p.RemoveConnection("b", "c"))
+
+	// there are two partitions now, and only the one with the start found first is walked
+	assert.Equal(t, slices.Collect(p.Points()), []string{"c", "d", "e"})
+	_, ok := p.Next("b")
+	assert.False(t, ok)
+	assert.Equal(t, p.Length(), 3)
+	assert.True(t, p.Contains("c"))
+}
+
+func TestPath_RemoveConnection_FailsIfTheConnectionDoesNotExist(t *testing.T) {
+	p := newTestPath(t)
+
+	assert.ErrorIs(t, p.RemoveConnection("a", "c"), ErrConnectionNotFound)
+	assert.ErrorIs(t, p.RemoveConnection("b", "a"), ErrConnectionNotFound)
+	assert.ErrorIs(t, p.RemoveConnection("z", "a"), ErrConnectionNotFound)
+	assert.Equal(t, p.Length(), 4)
+}
+
+func TestPath_RemoveConnection_Everything(t *testing.T) {
+	p := newTestPath(t)
+
+	for _, c := range [][2]string{{"b", "c"}, {"a", "b"}, {"d", "e"}, {"c", "d"}} {
+		assert.NoError(t, p.RemoveConnection(c[0], c[1]))
+	}
+
+	assert.Equal(t, p.Length(), 0)
+	assert.False(t, p.Contains("a"))
+	assert.True(t, p.Equal(NewPath[string]()))
+
+	assert.NoError(t, p.AddConnection("e", "a"))
+	assert.Equal(t, slices.Collect(p.Points()), []string{"e", "a"})
+}
+
+func TestPath_ReplaceConnection(t *testing.T) {
+	tests := []struct {
+		name                     string
+		from, to, newFrom, newTo string
+		want                     []string
+		err                      error
+	}{
+		{
+			name: "change the destination",
+			from: "d", to: "e", newFrom: "d", newTo: "f",
+			want: []string{"a", "b", "c", "d", "f"},
+		},
+		{
+			name: "change the origin",
+			from: "a", to: "b", newFrom: "z", newTo: "b",
+			want: []string{"z", "b", "c", "d", "e"},
+		},
+		{
+			name: "same connection",
+			from: "b", to: "c", newFrom: "b", newTo: "c",
+			want: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name: "connection not found",
+			from: "a", to: "c", newFrom: "a", newTo: "z",
+			err: ErrConnectionNotFound,
+		},
+		{
+			name: "same points",
+			from: "a", to: "b", newFrom: "z", newTo: "z",
+			err: ErrSamePoints,
+		},
+		{
+			name: "outbound connection exists",
+			from: "a", to: "b", newFrom: "c", newTo: "b",
+			err: ErrOutboundConnectionExists,
+		},
+		{
+			name: "inbound connection exists",
+			from: "a", to: "b", newFrom: "a", newTo: "d",
+			err: ErrInboundConnectionExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newTestPath(t)
+
+			err := p.ReplaceConnection(test.from, test.to, test.newFrom, test.newTo)
+
+			assert.ErrorIs(t, err, test.err)
+			if test.err != nil {
+				assert.True(t, p.Equal(newTestPath(t)), "the path was changed")
+				return
+			}
+			assert.Equal(t, slices.Collect(p.Points()), test.want)
+			assert.Equal(t, p.Length(), 4)
+		})
+	}
+}
+
+func BenchmarkPath_AddConnection(b *testing.B) {
+	for _, length := range []int{10, 1_000, 100_000} {
+		b.Run(fmt.Sprint(length), func(b *testing.B) {
+			legs := generator.New(42).Itinerary(length)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				path := NewPath[model.AirportCode]()
+				for _, leg := range legs {
+					if err := path.AddConnection(leg.Departure, leg.Arrival); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
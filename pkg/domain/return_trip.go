@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	ErrMissingFlightLegTimes = errors.New("unable to mirror the schedule - every flight leg must have departure and arrival times")
+	ErrNegativeStay          = errors.New("unable to mirror the schedule - the stay cannot be negative")
+)
+
+// ReturnTrip is the flight path back from the destination to the origin, which flies each flight leg the other way,
+// in reverse order. Departure and arrival times are not kept, since they would not make sense the other way; see
+// MirroredReturnTrip. Neither are warnings, since they are about the flight legs the way there: routes are directed,
+// so an unknown route may well be flown the other way.
+func ReturnTrip(flightPath *model.FlightPath) *model.FlightPath {
+	legs := make([]model.FlightLeg, len(flightPath.FlightLegs))
+	for i, leg := range flightPath.FlightLegs {
+		legs[len(legs)-1-i] = model.FlightLeg{
+			Departure: leg.Arrival,
+			Arrival:   leg.Departure,
+		}
+	}
+
+	return &model.FlightPath{
+		Origin:          flightPath.Destination,
+		Destination:     flightPath.Origin,
+		FlightLegs:      legs,
+		FlightLegFormat: flightPath.FlightLegFormat,
+	}
+}
+
+// MirroredReturnTrip is the ReturnTrip, scheduled as the mirror image of the flight path: it leaves the destination
+// once the stay is over, and every flight and every connection takes as long as it did on the way there. For example,
+// given a stay of 48 hours:
+//
+//	SFO → ATL, from day 1 at 08:00 to day 1 at 16:00
+//	ATL → GSO, from day 1 at 18:00 to day 1 at 19:00
+//
+// The return trip is:
+//
+//	GSO → ATL, from day 3 at 19:00 to day 3 at 20:00
+//	ATL → SFO, from day 3 at 22:00 to day 4 at 06:00
+//
+// Each time is in the same time zone as the time it mirrors, which is the time zone of the same airport.
+func MirroredReturnTrip(flightPath *model.FlightPath, stay time.Duration) (*model.FlightPath, error) {
+	if stay < 0 {
+		return nil, ErrNegativeStay
+	}
+
+	for _, leg := range flightPath.FlightLegs {
+		if leg.DepartureTime == nil || leg.ArrivalTime == nil {
+			return nil, fmt.Errorf("%w; the flight leg from %v to %v has none", ErrMissingFlightLegTimes,
+				leg.Departure, leg.Arrival)
+		}
+	}
+
+	returnTrip := ReturnTrip(flightPath)
+	if len(flightPath.FlightLegs) == 0 {
+		return returnTrip, nil
+	}
+
+	// every time is mirrored around the arrival at the destination, shifted by the stay
+	arrival := *flightPath.FlightLegs[len(flightPath.FlightLegs)-1].ArrivalTime
+	mirror := func(t time.Time) *time.Time {
+		mirrored := arrival.Add(stay).Add(arrival.Sub(t)).In(t.Location())
+		return &mirrored
+	}
+
+	for i, leg := range flightPath.FlightLegs {
+		returnLeg := &returnTrip.FlightLegs[len(returnTrip.FlightLegs)-1-i]
+		returnLeg.DepartureTime = mirror(*leg.ArrivalTime)
+		returnLeg.ArrivalTime = mirror(*leg.DepartureTime)
+	}
+
+	return returnTrip, nil
+}
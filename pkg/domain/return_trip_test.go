@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func timeOf(t *testing.T, value string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	assert.NoError(t, err)
+	return &parsed
+}
+
+func TestReturnTrip(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", DepartureTime: timeOf(t, "2024-03-25T08:00:00-07:00")},
+			{Departure: "ATL", Arrival: "GSO"},
+		},
+		Warnings: []model.Warning{{
+			Finding:   model.Finding{Err: ErrUnknownRoute},
+			FlightLeg: model.FlightLeg{Departure: "ATL", Arrival: "GSO"},
+		}},
+		FlightLegFormat: model.FlightLegFormatObject,
+	}
+
+	returnTrip := ReturnTrip(flightPath)
+
+	assert.Equal(t, returnTrip, &model.FlightPath{
+		Origin:      "GSO",
+		Destination: "SFO",
+		FlightLegs: []model.FlightLeg{
+			{Departure: "GSO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "SFO"},
+		},
+		FlightLegFormat: model.FlightLegFormatObject,
+	})
+	assert.Equal(t, ReturnTrip(returnTrip).FlightLegs, []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "ATL", Arrival: "GSO"},
+	})
+}
+
+func TestReturnTrip_OfACalculatedFlightPath(t *testing.T) {
+	flightPath, err := CalculateFlightPath([]model.FlightLeg{
+		{Departure: "ATL", Arrival: "EWR"},
+		{Departure: "SFO", Arrival: "ATL"},
+	})
+	assert.NoError(t, err)
+
+	returnTrip, err := CalculateFlightPath(ReturnTrip(flightPath).FlightLegs)
+	assert.NoError(t, err)
+	assert.Equal(t, returnTrip, ReturnTrip(flightPath))
+}
+
+func TestMirroredReturnTrip(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []model.FlightLeg{
+			{
+				Departure:     "SFO",
+				Arrival:       "ATL",
+				DepartureTime: timeOf(t, "2024-03-25T08:00:00-07:00"),
+				ArrivalTime:   timeOf(t, "2024-03-25T16:00:00-04:00"),
+			},
+			{
+				Departure:     "ATL",
+				Arrival:       "GSO",
+				DepartureTime: timeOf(t, "2024-03-25T18:00:00-04:00"),
+				ArrivalTime:   timeOf(t, "2024-03-25T19:00:00-04:00"),
+			},
+		},
+	}
+
+	returnTrip, err := MirroredReturnTrip(flightPath, 48*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, returnTrip.Origin, model.AirportCode("GSO"))
+	assert.Equal(t, returnTrip.Destination, model.AirportCode("SFO"))
+
+	want := []struct {
+		departure, arrival         model.AirportCode
+		departureTime, arrivalTime string
+	}{
+		{"GSO", "ATL", "2024-03-27T19:00:00-04:00", "2024-03-27T20:00:00-04:00"},
+		{"ATL", "SFO", "2024-03-27T22:00:00-04:00", "2024-03-28T00:00:00-07:00"},
+	}
+	assert.Len(t, returnTrip.FlightLegs, len(want))
+	for i, leg := range returnTrip.FlightLegs {
+		assert.Equal(t, leg.Departure, want[i].departure)
+		assert.Equal(t, leg.Arrival, want[i].arrival)
+		assert.Equal(t, leg.DepartureTime.Format(time.RFC3339), want[i].departureTime)
+		assert.Equal(t, leg.ArrivalTime.Format(time.RFC3339), want[i].arrivalTime)
+	}
+
+	// the flight path itself is left untouched
+	assert.Equal(t, flightPath.FlightLegs[0].DepartureTime.Format(time.RFC3339), "2024-03-25T08:00:00-07:00")
+}
+
+func TestMirroredReturnTrip_WithoutStay(t *testing.T) {
+	flightPath := &model.FlightPath{
+		Origin:      "SFO",
+		Destination: "ATL",
+		FlightLegs: []model.FlightLeg{
+			{
+				Departure:     "SFO",
+				Arrival:       "ATL",
+				DepartureTime: timeOf(t, "2024-03-25T08:00:00Z"),
+				ArrivalTime:   timeOf(t, "2024-03-25T13:00:00Z"),
+			},
+		},
+	}
+
+	returnTrip, err := MirroredReturnTrip(flightPath, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, returnTrip.FlightLegs, []model.FlightLeg{
+		{
+			Departure:     "ATL",
+			Arrival:       "SFO",
+			DepartureTime: timeOf(t, "2024-03-25T13:00:00Z"),
+			ArrivalTime:   timeOf(t, "2024-03-25T18:00:00Z"),
+		},
+	})
+}
+
+func TestMirroredReturnTrip_Errors(t *testing.T) {
+	departure := timeOf(t, "2024-03-25T08:00:00Z")
+	arrival := timeOf(t, "2024-03-25T13:00:00Z")
+
+	tests := []struct {
+		name string
+		legs []model.FlightLeg
+		stay time.Duration
+		err  error
+	}{
+		{
+			name: "negative stay",
+			legs: []model.FlightLeg{{Departure: "SFO", Arrival: "ATL", DepartureTime: departure, ArrivalTime: arrival}},
+			stay: -time.Hour,
+			err:  ErrNegativeStay,
+		},
+		{
+			name: "missing departure time",
+			legs: []model.FlightLeg{{Departure: "SFO", Arrival: "ATL", ArrivalTime: arrival}},
+			err:  ErrMissingFlightLegTimes,
+		},
+		{
+			name: "missing arrival time",
+			legs: []model.FlightLeg{
+				{Departure: "SFO", Arrival: "ATL", DepartureTime: departure, ArrivalTime: arrival},
+				{Departure: "ATL", Arrival: "GSO", DepartureTime: departure},
+			},
+			err: ErrMissingFlightLegTimes,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			returnTrip, err := MirroredReturnTrip(&model.FlightPath{FlightLegs: test.legs}, test.stay)
+
+			assert.Nil(t, returnTrip)
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+var (
+	ErrUnknownAirport = errors.New("unknown airport")
+	ErrRouteNotFound  = errors.New("no route found")
+)
+
+// RouteGraph tells where some airline flies nonstop to from an airport, such as according to a route dataset.
+type RouteGraph interface {
+	Destinations(departure model.AirportCode) []model.AirportCode
+}
+
+// AirportLocator tells where an airport is, such as according to an airports dataset.
+type AirportLocator interface {
+	Coordinates(airport model.AirportCode) (model.Coordinates, bool)
+}
+
+// FoundRoute is a route from one airport to another, along with its great-circle distance, in kilometers.
+type FoundRoute struct {
+	FlightPath *model.FlightPath
+	Distance   float64
+}
+
+// Stops is the number of airports the route stops at between its origin and its destination.
+func (r FoundRoute) Stops() int {
+	return len(r.FlightPath.FlightLegs) - 1
+}
+
+// RouteSearchResult has the route with the fewest stops, which is the shortest of them if there are several, and the
+// shortest route. They are often the same route.
+type RouteSearchResult struct {
+	FewestStops      FoundRoute
+	ShortestDistance FoundRoute
+}
+
+// RouteFinder searches a route graph for the routes between two airports. Only airports that the locator knows the
+// coordinates of are flown through.
+type RouteFinder struct {
+	graph   RouteGraph
+	locator AirportLocator
+}
+
+func NewRouteFinder(graph RouteGraph, locator AirportLocator) *RouteFinder {
+	return &RouteFinder{graph: graph, locator: locator}
+}
+
+// FindRoutes searches for the routes from one airport to another with up to maxStops stops. It fails with
+// ErrRouteNotFound if there's none, and with ErrUnknownAirport if the locator does not know either airport. It stops
+// as soon as possible if the context is canceled or its deadline is exceeded, returning an error that wraps the context
+// error.
+func (f *RouteFinder) FindRoutes(
+	ctx context.Context, from, to model.AirportCode, maxStops int,
+) (*RouteSearchResult, error) {
+	if from == to {
+		return nil, fmt.Errorf("%w; %w", ErrInvalidFlightPath, ErrSamePoints)
+	}
+	for _, airport := range []model.AirportCode{from, to} {
+		if _, ok := f.locator.Coordinates(airport); !ok {
+			return nil, fmt.Errorf("%w %v", ErrUnknownAirport, airport)
+		}
+	}
+
+	fewestStops, err := f.search(ctx, from, to, maxStops, fewerLegs)
+	if err != nil {
+		return nil, err
+	}
+	shortestDistance, err := f.search(ctx, from, to, maxStops, shorterDistance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteSearchResult{FewestStops: *fewestStops, ShortestDistance: *shortestDistance}, nil
+}
+
+// routeCost is how far a route has gone, in flight legs and in kilometers.
+type routeCost struct {
+	legs     int
+	distance float64
+}
+
+func (c routeCost) plus(other routeCost) routeCost {
+	return routeCost{legs: c.legs + other.legs, distance: c.distance + other.distance}
+}
+
+// routeObjective orders route costs by what the search minimizes first, breaking ties by the other.
+type routeObjective func(a, b routeCost) int
+
+func fewerLegs(a, b routeCost) int {
+	return cmp.Or(cmp.Compare(a.legs, b.legs), cmp.Compare(a.distance, b.distance))
+}
+
+func shorterDistance(a, b routeCost) int {
+	return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.legs, b.legs))
+}
+
+// routeState is an airport reached after a number of flight legs. The same airport is a different state when reached
+// with fewer legs, since more stops are left to go further from it.
+type routeState struct {
+	airport model.AirportCode
+	legs    int
+}
+
+type routeNode struct {
+	routeState
+	cost routeCost
+	// estimate is the cost so far plus the least cost left to the destination
+	estimate routeCost
+	previous *routeNode
+	// order breaks ties between equal estimates, so that the search is deterministic
+	order int
+}
+
+// search is an A* search over the route graph. The heuristic is the great-circle distance left to the destination,
+// along with one more flight leg if it wasn't reached yet. Since no flight leg can be shorter than the great-circle
+// distance between its airports, the heuristic never overestimates, so the first route that reaches the destination is
+// the best one.
+func (f *RouteFinder) search(
+	ctx context.Context, from, to model.AirportCode, maxStops int, objective routeObjective,
+) (*FoundRoute, error) {
+	destination, _ := f.locator.Coordinates(to)
+	heuristic := func(airport model.AirportCode, coordinates model.Coordinates) routeCost {
+		if airport == to {
+			return routeCost{}
+		}
+		return routeCost{legs: 1, distance: coordinates.DistanceTo(destination)}
+	}
+
+	origin, _ := f.locator.Coordinates(from)
+	open := &routeQueue{objective: objective}
+	heap.Push(open, &routeNode{
+		routeState: routeState{airport: from},
+		estimate:   heuristic(from, origin),
+	})
+	best := map[routeState]routeCost{{airport: from}: {}}
+	closed := make(map[routeState]bool)
+
+	for i := 0; open.Len() > 0; i++ {
+		if err := checkCanceled(ctx, i); err != nil {
+			return nil, err
+		}
+
+		node := heap.Pop(open).(*routeNode)
+		if node.airport == to {
+			return newFoundRoute(node), nil
+		}
+		if closed[node.routeState] {
+			continue
+		}
+		closed[node.routeState] = true
+
+		coordinates, _ := f.locator.Coordinates(node.airport)
+		for _, next := range f.graph.Destinations(node.airport) {
+			nextCoordinates, ok := f.locator.Coordinates(next)
+			if !ok {
+				continue
+			}
+
+			state := routeState{airport: next, legs: node.legs + 1}
+			if state.legs > maxStops && next != to {
+				continue
+			}
+			cost := node.cost.plus(routeCost{legs: 1, distance: coordinates.DistanceTo(nextCoordinates)})
+			if previous, ok := best[state]; closed[state] || (ok && objective(previous, cost) <= 0) {
+				continue
+			}
+			best[state] = cost
+
+			heap.Push(open, &routeNode{
+				routeState: state,
+				cost:       cost,
+				estimate:   cost.plus(heuristic(next, nextCoordinates)),
+				previous:   node,
+				order:      open.pushed,
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("%w from %v to %v with up to %v stops", ErrRouteNotFound, from, to, maxStops)
+}
+
+func newFoundRoute(node *routeNode) *FoundRoute {
+	legs := make([]model.FlightLeg, node.legs)
+	for n := node; n.previous != nil; n = n.previous {
+		legs[n.legs-1] = model.FlightLeg{Departure: n.previous.airport, Arrival: n.airport}
+	}
+
+	return &FoundRoute{
+		FlightPath: &model.FlightPath{
+			Origin:      legs[0].Departure,
+			Destination: node.airport,
+			FlightLegs:  legs,
+		},
+		Distance: node.cost.distance,
+	}
+}
+
+// routeQueue is a priority queue of the nodes to visit, starting with the least estimate according to the objective.
+type routeQueue struct {
+	nodes     []*routeNode
+	objective routeObjective
+	pushed    int
+}
+
+func (q *routeQueue) Len() int {
+	return len(q.nodes)
+}
+
+func (q *routeQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	return cmp.Or(q.objective(a.estimate, b.estimate), cmp.Compare(a.order, b.order)) < 0
+}
+
+func (q *routeQueue) Swap(i, j int) {
+	q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i]
+}
+
+func (q *routeQueue) Push(x any) {
+	q.nodes = append(q.nodes, x.(*routeNode))
+	q.pushed++
+}
+
+func (q *routeQueue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes[len(q.nodes)-1] = nil
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// routeGraph is a RouteGraph of the given destinations by departure
+type routeGraph map[model.AirportCode][]model.AirportCode
+
+func (g routeGraph) Destinations(departure model.AirportCode) []model.AirportCode {
+	return g[departure]
+}
+
+// airportLocator is an AirportLocator of the given coordinates by airport
+type airportLocator map[model.AirportCode]model.Coordinates
+
+func (l airportLocator) Coordinates(airport model.AirportCode) (model.Coordinates, bool) {
+	coordinates, ok := l[airport]
+	return coordinates, ok
+}
+
+var testAirports = airportLocator{
+	"SFO": {Latitude: 37.61899948120117, Longitude: -122.375},
+	"DEN": {Latitude: 39.861698150635, Longitude: -104.672996521},
+	"ORD": {Latitude: 41.9786, Longitude: -87.9048},
+	"GSO": {Latitude: 36.097801208496094, Longitude: -79.93730163574219},
+	"LHR": {Latitude: 51.4706, Longitude: -0.461941},
+}
+
+// testRoutes go from SFO to GSO either through LHR, with a single but very long stop, or through DEN and ORD. The
+// route through XXX is the shortest, but the coordinates of XXX are unknown.
+var testRoutes = routeGraph{
+	"SFO": {"LHR", "DEN", "XXX"},
+	"LHR": {"GSO", "SFO"},
+	"DEN": {"ORD", "SFO"},
+	"ORD": {"GSO", "DEN"},
+	"XXX": {"GSO"},
+}
+
+func TestRouteFinder_FindRoutes(t *testing.T) {
+	viaLHR := []model.FlightLeg{{Departure: "SFO", Arrival: "LHR"}, {Departure: "LHR", Arrival: "GSO"}}
+	viaDENAndORD := []model.FlightLeg{
+		{Departure: "SFO", Arrival: "DEN"},
+		{Departure: "DEN", Arrival: "ORD"},
+		{Departure: "ORD", Arrival: "GSO"},
+	}
+
+	tests := []struct {
+		name                 string
+		maxStops             int
+		wantFewestStops      []model.FlightLeg
+		wantShortestDistance []model.FlightLeg
+	}{
+		{"with up to 2 stops", 2, viaLHR, viaDENAndORD},
+		{"with up to 1 stop", 1, viaLHR, viaLHR},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			finder := NewRouteFinder(testRoutes, testAirports)
+
+			result, err := finder.FindRoutes(context.Background(), "SFO", "GSO", test.maxStops)
+			assert.NoError(t, err)
+
+			assert.Equal(t, result.FewestStops.FlightPath, &model.FlightPath{
+				Origin: "SFO", Destination: "GSO", FlightLegs: test.wantFewestStops,
+			})
+			assert.Equal(t, result.ShortestDistance.FlightPath, &model.FlightPath{
+				Origin: "SFO", Destination: "GSO", FlightLegs: test.wantShortestDistance,
+			})
+			assert.Equal(t, result.FewestStops.Stops(), 1)
+			assert.Equal(t, result.ShortestDistance.Stops(), len(test.wantShortestDistance)-1)
+		})
+	}
+}
+
+func TestRouteFinder_FindRoutes_Distance(t *testing.T) {
+	finder := NewRouteFinder(testRoutes, testAirports)
+
+	result, err := finder.FindRoutes(context.Background(), "SFO", "GSO", 2)
+	assert.NoError(t, err)
+
+	sfo, den, ord, gso := testAirports["SFO"], testAirports["DEN"], testAirports["ORD"], testAirports["GSO"]
+	assert.InDelta(t, result.ShortestDistance.Distance, sfo.DistanceTo(den)+den.DistanceTo(ord)+ord.DistanceTo(gso), 1e-6)
+	assert.Greater(t, result.FewestStops.Distance, result.ShortestDistance.Distance)
+}
+
+func TestRouteFinder_FindRoutes_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  model.AirportCode
+		maxStops  int
+		wantErr   error
+		wantError string
+	}{
+		{
+			name:      "nonstop",
+			from:      "SFO",
+			to:        "GSO",
+			maxStops:  0,
+			wantErr:   ErrRouteNotFound,
+			wantError: "no route found from SFO to GSO with up to 0 stops",
+		},
+		{
+			name:      "unreachable destination",
+			from:      "GSO",
+			to:        "SFO",
+			maxStops:  3,
+			wantErr:   ErrRouteNotFound,
+			wantError: "no route found from GSO to SFO with up to 3 stops",
+		},
+		{
+			name:      "unknown departure",
+			from:      "XXX",
+			to:        "GSO",
+			maxStops:  1,
+			wantErr:   ErrUnknownAirport,
+			wantError: "unknown airport XXX",
+		},
+		{
+			name:      "unknown arrival",
+			from:      "SFO",
+			to:        "YYY",
+			maxStops:  1,
+			wantErr:   ErrUnknownAirport,
+			wantError: "unknown airport YYY",
+		},
+		{
+			name:      "same airports",
+			from:      "SFO",
+			to:        "SFO",
+			maxStops:  1,
+			wantErr:   ErrSamePoints,
+			wantError: "invalid flight path; invalid connection - \"from\" and \"to\" are the same",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			finder := NewRouteFinder(testRoutes, testAirports)
+
+			result, err := finder.FindRoutes(context.Background(), test.from, test.to, test.maxStops)
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.EqualError(t, err, test.wantError)
+		})
+	}
+}
+
+func TestRouteFinder_FindRoutes_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := NewRouteFinder(testRoutes, testAirports).FindRoutes(ctx, "SFO", "GSO", 2)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, context.Canceled)
+}
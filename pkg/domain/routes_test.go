@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// knownRoutes is a RouteNetwork of the given routes, such as "SFO-ATL"
+type knownRoutes map[string]bool
+
+func (r knownRoutes) HasRoute(departure, arrival model.AirportCode) bool {
+	return r[string(departure)+"-"+string(arrival)]
+}
+
+func TestCalculateFlightPathWithOptions_UnknownRoutes(t *testing.T) {
+	flightLegs := []model.FlightLeg{
+		{Departure: "GSO", Arrival: "IND"},
+		{Departure: "ATL", Arrival: "GSO"},
+		{Departure: "SFO", Arrival: "ATL"},
+	}
+	routes := knownRoutes{"SFO-ATL": true, "ATL-GSO": true}
+
+	t.Run("warning", func(t *testing.T) {
+		got, err := CalculateFlightPathWithOptions(context.Background(), flightLegs, CalculationOptions{Routes: routes})
+		assert.NoError(t, err)
+		assert.Equal(t, got.FlightLegs, []model.FlightLeg{flightLegs[2], flightLegs[1], flightLegs[0]})
+
+		assert.Len(t, got.Warnings, 1)
+		assert.ErrorIs(t, got.Warnings[0].Err, ErrUnknownRoute)
+		assert.EqualError(t, got.Warnings[0].Err, "no airline is known to fly the route from GSO to IND")
+		assert.Equal(t, got.Warnings[0].FlightLeg, flightLegs[0])
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		got, err := CalculateFlightPathWithOptions(context.Background(), flightLegs,
+			CalculationOptions{Routes: routes, StrictRoutes: true})
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, ErrInvalidFlightPath)
+		assert.ErrorIs(t, err, ErrUnknownRoute)
+	})
+
+	t.Run("every route is known", func(t *testing.T) {
+		routes := knownRoutes{"SFO-ATL": true, "ATL-GSO": true, "GSO-IND": true}
+		got, err := CalculateFlightPathWithOptions(context.Background(), flightLegs,
+			CalculationOptions{Routes: routes, StrictRoutes: true})
+		assert.NoError(t, err)
+		assert.Empty(t, got.Warnings)
+	})
+}
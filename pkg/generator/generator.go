@@ -0,0 +1,145 @@
+// Package generator produces synthetic itineraries of any size, to benchmark and stress test the calculation of
+// flight paths. The same seed always produces the same itineraries. The pathological ones need a few flight legs to be
+// what they claim, so NearLoop and ManyBranches document their minimum length.
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// PlainAirportCodes is the number of distinct plain IATA airport codes, from AAA to ZZZ. Valid itineraries can have at
+// most one flight leg less than that, unless they use longer codes.
+const PlainAirportCodes = 26 * 26 * 26
+
+type Generator struct {
+	rand *rand.Rand
+}
+
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// AirportCodes returns n distinct airport codes in random order. They are plain IATA codes, made of three uppercase
+// letters, as long as n is at most PlainAirportCodes. Beyond that, codes with more letters are used as well.
+func (g *Generator) AirportCodes(n int) []model.AirportCode {
+	codes := make([]model.AirportCode, n)
+	for i, code := range g.rand.Perm(n) {
+		codes[i] = airportCode(code)
+	}
+	return codes
+}
+
+// airportCode converts a number into an airport code: AAA to ZZZ for the first PlainAirportCodes numbers, then AAAA,
+// AAAB, and so on.
+func airportCode(n int) model.AirportCode {
+	letters := 3
+	for size := PlainAirportCodes; n >= size; size *= 26 {
+		n -= size
+		letters++
+	}
+
+	code := make([]byte, letters)
+	for i := letters - 1; i >= 0; i-- {
+		code[i] = byte('A' + n%26)
+		n /= 26
+	}
+	return model.AirportCode(code)
+}
+
+// Chain returns a valid itinerary with the given number of flight legs, sorted from the origin to the destination.
+func (g *Generator) Chain(length int) []model.FlightLeg {
+	codes := g.AirportCodes(length + 1)
+
+	legs := make([]model.FlightLeg, length)
+	for i := range legs {
+		legs[i] = model.FlightLeg{Departure: codes[i], Arrival: codes[i+1]}
+	}
+	return legs
+}
+
+// Itinerary returns a valid itinerary with the given number of flight legs, in random order.
+func (g *Generator) Itinerary(length int) []model.FlightLeg {
+	legs := g.Chain(length)
+	g.Shuffle(legs)
+	return legs
+}
+
+// LongChain returns a valid itinerary with the given number of flight legs, sorted from the destination back to the
+// origin, which is the worst order for walking the flight path as it is being built.
+func (g *Generator) LongChain(length int) []model.FlightLeg {
+	legs := g.Chain(length)
+	for i, j := 0, len(legs)-1; i < j; i, j = i+1, j-1 {
+		legs[i], legs[j] = legs[j], legs[i]
+	}
+	return legs
+}
+
+// NearLoop returns an invalid itinerary with the given number of flight legs, in random order, where the last flight
+// leg flies back to the origin. Every flight leg is consistent with the others, so the loop is only detected once all
+// of them have been connected. The length must be at least 2, since a single flight leg cannot fly back to where it
+// departs from; it panics otherwise.
+func (g *Generator) NearLoop(length int) []model.FlightLeg {
+	if length < 2 {
+		panic(fmt.Sprintf("generator: a near loop needs at least 2 flight legs, not %v", length))
+	}
+
+	legs := g.Chain(length)
+	legs[length-1].Arrival = legs[0].Departure
+	g.Shuffle(legs)
+	return legs
+}
+
+// ManyBranches returns an invalid itinerary with a valid chain of the given length, in random order, followed by
+// the given number of branches: flight legs that leave from an airport of the chain to a new airport. The length must
+// be at least 1, so that there is an airport to branch from; it panics otherwise.
+func (g *Generator) ManyBranches(length, branches int) []model.FlightLeg {
+	if length < 1 {
+		panic(fmt.Sprintf("generator: branches need a chain of at least 1 flight leg, not %v", length))
+	}
+
+	codes := g.AirportCodes(length + 1 + branches)
+
+	legs := make([]model.FlightLeg, length, length+branches)
+	for i := range legs {
+		legs[i] = model.FlightLeg{Departure: codes[i], Arrival: codes[i+1]}
+	}
+	g.Shuffle(legs)
+
+	for i := 0; i < branches; i++ {
+		legs = append(legs, model.FlightLeg{
+			Departure: codes[g.rand.Intn(length)],
+			Arrival:   codes[length+1+i],
+		})
+	}
+	return legs
+}
+
+func (g *Generator) Shuffle(legs []model.FlightLeg) {
+	g.rand.Shuffle(len(legs), func(i, j int) {
+		legs[i], legs[j] = legs[j], legs[i]
+	})
+}
+
+// RequestBody encodes the flight legs as the JSON body of a request to calculate their flight path, with each flight
+// leg in the given format.
+func RequestBody(legs []model.FlightLeg, format model.FlightLegFormat) ([]byte, error) {
+	rawLegs := make([]json.RawMessage, len(legs))
+	for i, leg := range legs {
+		rawLeg, err := leg.MarshalJSONFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		rawLegs[i] = rawLeg
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(struct {
+		FlightLegs []json.RawMessage `json:"flight_legs"`
+	}{rawLegs})
+	return body.Bytes(), err
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/domain"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestAirportCode(t *testing.T) {
+	tests := []struct {
+		n    int
+		want model.AirportCode
+	}{
+		{0, "AAA"},
+		{27, "ABB"},
+		{PlainAirportCodes - 1, "ZZZ"},
+		{PlainAirportCodes, "AAAA"},
+		{PlainAirportCodes + 27, "AABB"},
+		{PlainAirportCodes + 26*PlainAirportCodes, "AAAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			assert.Equal(t, airportCode(tt.n), tt.want)
+		})
+	}
+}
+
+func TestGenerator_AirportCodes(t *testing.T) {
+	codes := New(1).AirportCodes(PlainAirportCodes)
+
+	distinct := make(map[model.AirportCode]bool)
+	for _, code := range codes {
+		assert.True(t, code.IsValid(), code)
+		distinct[code] = true
+	}
+	assert.Len(t, distinct, PlainAirportCodes)
+}
+
+func TestGenerator_SameSeedSameItinerary(t *testing.T) {
+	assert.Equal(t, New(7).Itinerary(100), New(7).Itinerary(100))
+	assert.NotEqual(t, New(7).Itinerary(100), New(8).Itinerary(100))
+}
+
+func TestGenerator_Itinerary(t *testing.T) {
+	for _, length := range []int{1, 10, PlainAirportCodes - 1, PlainAirportCodes + 10} {
+		legs := New(1).Itinerary(length)
+
+		flightPath, err := domain.CalculateFlightPath(legs)
+		assert.NoError(t, err)
+		assert.Len(t, flightPath.FlightLegs, length)
+	}
+}
+
+func TestGenerator_Chain(t *testing.T) {
+	legs := New(1).Chain(100)
+
+	for i := 1; i < len(legs); i++ {
+		assert.Equal(t, legs[i].Departure, legs[i-1].Arrival)
+	}
+}
+
+func TestGenerator_LongChain(t *testing.T) {
+	legs := New(1).LongChain(100)
+
+	for i := 1; i < len(legs); i++ {
+		assert.Equal(t, legs[i].Arrival, legs[i-1].Departure)
+	}
+
+	flightPath, err := domain.CalculateFlightPath(legs)
+	assert.NoError(t, err)
+	assert.Equal(t, flightPath.Origin, legs[len(legs)-1].Departure)
+	assert.Equal(t, flightPath.Destination, legs[0].Arrival)
+}
+
+func TestGenerator_NearLoop(t *testing.T) {
+	for _, length := range []int{2, 100} {
+		_, err := domain.CalculateFlightPath(New(1).NearLoop(length))
+
+		assert.ErrorIs(t, err, domain.ErrStartNotFound)
+	}
+}
+
+func TestGenerator_NearLoopTooShort(t *testing.T) {
+	for _, length := range []int{0, 1} {
+		assert.Panics(t, func() { New(1).NearLoop(length) })
+	}
+}
+
+func TestGenerator_ManyBranches(t *testing.T) {
+	legs := New(1).ManyBranches(100, 20)
+	assert.Len(t, legs, 120)
+
+	_, err := domain.CalculateFlightPath(legs)
+	assert.ErrorIs(t, err, domain.ErrOutboundConnectionExists)
+
+	// the chain alone is valid
+	_, err = domain.CalculateFlightPath(legs[:100])
+	assert.NoError(t, err)
+}
+
+func TestGenerator_ManyBranchesOfTheShortestChain(t *testing.T) {
+	legs := New(1).ManyBranches(1, 5)
+	assert.Len(t, legs, 6)
+
+	_, err := domain.CalculateFlightPath(legs)
+	assert.ErrorIs(t, err, domain.ErrOutboundConnectionExists)
+
+	assert.Panics(t, func() { New(1).ManyBranches(0, 5) })
+}
+
+func TestRequestBody(t *testing.T) {
+	legs := []model.FlightLeg{
+		{Departure: "SFO", Arrival: "ATL"},
+		{Departure: "ATL", Arrival: "EWR"},
+	}
+
+	body, err := RequestBody(legs, model.FlightLegFormatString)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(body), `{"flight_legs": ["SFO-ATL", "ATL-EWR"]}`)
+
+	var decoded struct {
+		FlightLegs []model.FlightLeg `json:"flight_legs"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, decoded.FlightLegs, legs)
+}
@@ -0,0 +1,66 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/felipead/flight-path-tracker/pkg/generator"
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func BenchmarkFlightLeg_UnmarshalJSON(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		data string
+	}{
+		{"array", `["SFO", "ATL"]`},
+		{"object", `{"departure": "SFO", "arrival": "ATL"}`},
+		{"object with times", `{"departure": "SFO", "arrival": "ATL", ` +
+			`"departure_time": "2024-03-25T08:05:00-07:00", "arrival_time": "2024-03-25T15:40:00-04:00"}`},
+		{"string", `"SFO-ATL"`},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			data := []byte(bm.data)
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				var leg model.FlightLeg
+				if err := leg.UnmarshalJSON(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFlightLegs_UnmarshalJSON decodes the flight legs of a whole generated itinerary
+func BenchmarkFlightLegs_UnmarshalJSON(b *testing.B) {
+	legs := generator.New(42).Itinerary(10_000)
+
+	for _, format := range []model.FlightLegFormat{
+		model.FlightLegFormatArray,
+		model.FlightLegFormatObject,
+		model.FlightLegFormatString,
+	} {
+		b.Run(string(format), func(b *testing.B) {
+			body, err := generator.RequestBody(legs, format)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var request struct {
+					FlightLegs []model.FlightLeg `json:"flight_legs"`
+				}
+				if err := json.Unmarshal(body, &request); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
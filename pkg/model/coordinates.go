@@ -0,0 +1,28 @@
+package model
+
+import "math"
+
+// EarthRadius is the mean radius of the Earth, in kilometers.
+const EarthRadius = 6371.0
+
+// Coordinates are the location of an airport, in decimal degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceTo is the great-circle distance to the other coordinates, in kilometers. It is calculated with the
+// haversine formula, which assumes that the Earth is a sphere, so it can be off by up to 0.5%.
+func (c Coordinates) DistanceTo(other Coordinates) float64 {
+	lat1 := radians(c.Latitude)
+	lat2 := radians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(other.Longitude - c.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinates_DistanceTo(t *testing.T) {
+	sfo := Coordinates{Latitude: 37.61899948120117, Longitude: -122.375}
+	atl := Coordinates{Latitude: 33.6367, Longitude: -84.428101}
+	lhr := Coordinates{Latitude: 51.4706, Longitude: -0.461941}
+	gru := Coordinates{Latitude: -23.435556411743164, Longitude: -46.47305679321289}
+
+	tests := []struct {
+		name     string
+		from, to Coordinates
+		want     float64
+	}{
+		{"same coordinates", sfo, sfo, 0},
+		{"SFO to ATL", sfo, atl, 3438},
+		{"ATL to SFO", atl, sfo, 3438},
+		{"SFO to LHR", sfo, lhr, 8616},
+		{"across the equator", lhr, gru, 9460},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.from.DistanceTo(test.to), test.want, 5)
+		})
+	}
+}
@@ -1,44 +1,301 @@
 package model
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
+var (
+	ErrInvalidFlightLeg      = errors.New("unable to unmarshal flight leg")
+	ErrUnknownFlightLegField = errors.New("unknown flight leg field")
+	ErrInvalidFlightLegTime  = errors.New("invalid flight leg time")
+)
+
+// FlightLegFormat is one of the JSON representations of a flight leg.
+type FlightLegFormat string
+
+const (
+	// FlightLegFormatArray is the default format, such as ["SFO", "ORD"]
+	FlightLegFormatArray FlightLegFormat = "array"
+	// FlightLegFormatObject is such as {"departure": "SFO", "arrival": "ORD"}, and the only format that can carry the
+	// departure and arrival times
+	FlightLegFormatObject FlightLegFormat = "object"
+	// FlightLegFormatString is such as "SFO-ORD"
+	FlightLegFormatString FlightLegFormat = "string"
+)
+
+const (
+	departureField     = "departure"
+	arrivalField       = "arrival"
+	departureTimeField = "departure_time"
+	arrivalTimeField   = "arrival_time"
+)
+
+var flightLegObjectFields = map[string]bool{
+	departureField:     true,
+	arrivalField:       true,
+	departureTimeField: true,
+	arrivalTimeField:   true,
+}
+
+// FlightLegError tells why a flight leg could not be unmarshalled, and which value caused it.
+type FlightLegError struct {
+	// Position is the index of the offending airport code within a flight leg in the array format, or -1 if the
+	// flight leg as a whole is invalid.
+	Position int
+	// Field is the name of the offending field within a flight leg in the object format, if any.
+	Field string
+	// Value is the offending JSON value.
+	Value interface{}
+
+	reason string
+	err    error
+}
+
+func (e *FlightLegError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrInvalidFlightLeg, e.reason)
+}
+
+func (e *FlightLegError) Unwrap() []error {
+	if e.err == nil {
+		return []error{ErrInvalidFlightLeg}
+	}
+	return []error{ErrInvalidFlightLeg, e.err}
+}
+
 type FlightLeg struct {
 	Departure AirportCode `validate:"required,airport_code"`
 	Arrival   AirportCode `validate:"required,airport_code"`
+
+	// DepartureTime and ArrivalTime are optional, since not every input format is able to carry them.
+	DepartureTime *time.Time
+	ArrivalTime   *time.Time
+
+	// Emissions is an optional estimate of the emissions of the flight leg. It is marshalled within the flight leg in
+	// the object format, and along with the flight path in the other formats.
+	Emissions *Emissions
 }
 
+// Emissions is an estimate of the CO2 emitted on behalf of a single traveler.
+type Emissions struct {
+	// Distance is the distance the estimate is based on, in kilometers
+	Distance float64 `json:"distance_km"`
+	// CO2 is in kilograms
+	CO2 float64 `json:"co2_kg"`
+}
+
+// UnmarshalJSON accepts a flight leg in any FlightLegFormat. Unknown fields of the object format are ignored.
 func (leg *FlightLeg) UnmarshalJSON(data []byte) error {
+	parsed, _, err := UnmarshalFlightLeg(data, false)
+	if err != nil {
+		return err
+	}
+
+	*leg = parsed
+	return nil
+}
+
+// UnmarshalFlightLeg decodes a flight leg in any FlightLegFormat, and tells which format it was given in. If strict
+// is true, a flight leg in the object format cannot have any fields besides the known ones.
+func UnmarshalFlightLeg(data []byte, strict bool) (FlightLeg, FlightLegFormat, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		leg, err := unmarshalFlightLegObject(data, strict)
+		return leg, FlightLegFormatObject, err
+	case len(trimmed) > 0 && trimmed[0] == '"':
+		leg, err := unmarshalFlightLegString(data)
+		return leg, FlightLegFormatString, err
+	default:
+		leg, err := unmarshalFlightLegArray(data)
+		return leg, FlightLegFormatArray, err
+	}
+}
+
+func unmarshalFlightLegArray(data []byte) (FlightLeg, error) {
 	var v []interface{}
 	if err := json.Unmarshal(data, &v); err != nil {
-		return fmt.Errorf("unable to unmarshal flight leg: %w", err)
+		return FlightLeg{}, &FlightLegError{Position: -1, Value: json.RawMessage(data), reason: err.Error(), err: err}
 	}
 
 	if len(v) != 2 {
-		return fmt.Errorf("unable to unmarshal flight leg: JSON array contains %v entries", len(v))
+		return FlightLeg{}, &FlightLegError{
+			Position: -1,
+			Value:    v,
+			reason:   fmt.Sprintf("JSON array contains %v entries", len(v)),
+		}
 	}
 
 	var ok bool
 	var departure, arrival string
 
 	if departure, ok = v[0].(string); !ok {
-		return fmt.Errorf("unable to unmarshal flight leg: departure code is not a string")
+		return FlightLeg{}, &FlightLegError{Position: 0, Value: v[0], reason: "departure code is not a string"}
 	}
 	if arrival, ok = v[1].(string); !ok {
-		return fmt.Errorf("unable to unmarshal flight leg: arrival code is not a string")
+		return FlightLeg{}, &FlightLegError{Position: 1, Value: v[1], reason: "arrival code is not a string"}
 	}
 
-	leg.Departure = AirportCode(departure)
-	leg.Arrival = AirportCode(arrival)
+	return FlightLeg{
+		Departure: AirportCode(departure),
+		Arrival:   AirportCode(arrival),
+	}, nil
+}
 
-	return nil
+func unmarshalFlightLegObject(data []byte, strict bool) (FlightLeg, error) {
+	var v map[string]interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return FlightLeg{}, &FlightLegError{Position: -1, Value: json.RawMessage(data), reason: err.Error(), err: err}
+	}
+
+	if strict {
+		fields := make([]string, 0, len(v))
+		for field := range v {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			if !flightLegObjectFields[field] {
+				return FlightLeg{}, &FlightLegError{
+					Position: -1,
+					Field:    field,
+					Value:    v[field],
+					reason:   fmt.Sprintf("%v %q", ErrUnknownFlightLegField, field),
+					err:      ErrUnknownFlightLegField,
+				}
+			}
+		}
+	}
+
+	//
+	// Missing airport codes are left empty, so that they are reported by the validator like in the other formats.
+	//
+
+	var err error
+	var leg FlightLeg
+
+	if value, found := v[departureField]; found {
+		departure, ok := value.(string)
+		if !ok {
+			return FlightLeg{}, &FlightLegError{
+				Position: -1,
+				Field:    departureField,
+				Value:    value,
+				reason:   "departure code is not a string",
+			}
+		}
+		leg.Departure = AirportCode(departure)
+	}
+
+	if value, found := v[arrivalField]; found {
+		arrival, ok := value.(string)
+		if !ok {
+			return FlightLeg{}, &FlightLegError{
+				Position: -1,
+				Field:    arrivalField,
+				Value:    value,
+				reason:   "arrival code is not a string",
+			}
+		}
+		leg.Arrival = AirportCode(arrival)
+	}
+
+	if leg.DepartureTime, err = unmarshalFlightLegTime(v, departureTimeField); err != nil {
+		return FlightLeg{}, err
+	}
+	if leg.ArrivalTime, err = unmarshalFlightLegTime(v, arrivalTimeField); err != nil {
+		return FlightLeg{}, err
+	}
+
+	return leg, nil
+}
+
+func unmarshalFlightLegTime(v map[string]interface{}, field string) (*time.Time, error) {
+	value, found := v[field]
+	if !found || value == nil {
+		return nil, nil
+	}
+
+	if text, ok := value.(string); ok {
+		if t, err := ParseFlightLegTime(text); err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, &FlightLegError{
+		Position: -1,
+		Field:    field,
+		Value:    value,
+		reason:   fmt.Sprintf("%v is not an RFC 3339 timestamp", field),
+		err:      ErrInvalidFlightLegTime,
+	}
+}
+
+// ParseFlightLegTime parses departure and arrival times, which must be in the RFC 3339 format, such as
+// "2024-03-25T14:30:00Z". The time zone offset is kept, since it is meaningful to travelers.
+func ParseFlightLegTime(text string) (time.Time, error) {
+	return time.Parse(time.RFC3339, text)
+}
+
+func unmarshalFlightLegString(data []byte) (FlightLeg, error) {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return FlightLeg{}, &FlightLegError{Position: -1, Value: json.RawMessage(data), reason: err.Error(), err: err}
+	}
+
+	departure, arrival, found := strings.Cut(v, "-")
+	if !found || strings.Contains(arrival, "-") {
+		return FlightLeg{}, &FlightLegError{
+			Position: -1,
+			Value:    v,
+			reason:   "string is not in the DEPARTURE-ARRIVAL format",
+		}
+	}
+
+	return FlightLeg{
+		Departure: AirportCode(departure),
+		Arrival:   AirportCode(arrival),
+	}, nil
+}
+
+type flightLegObject struct {
+	Departure     AirportCode `json:"departure"`
+	Arrival       AirportCode `json:"arrival"`
+	DepartureTime *time.Time  `json:"departure_time,omitempty"`
+	ArrivalTime   *time.Time  `json:"arrival_time,omitempty"`
+	Emissions     *Emissions  `json:"emissions,omitempty"`
 }
 
 func (leg *FlightLeg) MarshalJSON() ([]byte, error) {
-	return json.Marshal([]string{
-		string(leg.Departure),
-		string(leg.Arrival),
-	})
+	return leg.MarshalJSONFormat(FlightLegFormatArray)
+}
+
+// MarshalJSONFormat encodes the flight leg in the given format. An empty format means FlightLegFormatArray.
+func (leg *FlightLeg) MarshalJSONFormat(format FlightLegFormat) ([]byte, error) {
+	switch format {
+	case FlightLegFormatArray, "":
+		return json.Marshal([]string{
+			string(leg.Departure),
+			string(leg.Arrival),
+		})
+	case FlightLegFormatObject:
+		return json.Marshal(flightLegObject{
+			Departure:     leg.Departure,
+			Arrival:       leg.Arrival,
+			DepartureTime: leg.DepartureTime,
+			ArrivalTime:   leg.ArrivalTime,
+			Emissions:     leg.Emissions,
+		})
+	case FlightLegFormatString:
+		return json.Marshal(string(leg.Departure) + "-" + string(leg.Arrival))
+	default:
+		return nil, fmt.Errorf("unsupported flight leg format %q", format)
+	}
 }
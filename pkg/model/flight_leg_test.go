@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -42,13 +43,140 @@ func TestFlightLeg_UnmarshalJSON_ErrorInvalidJSON(t *testing.T) {
 }
 
 func TestFlightLeg_UnmarshalJSON_ErrorNotAJSONArray(t *testing.T) {
-	payload := `{"departure": "SFO", "arrival": "ORD"}`
+	payload := `5`
 
 	var leg FlightLeg
 
 	err := json.Unmarshal([]byte(payload), &leg)
 
-	assert.ErrorContains(t, err, "cannot unmarshal object into Go value of type []interface {}")
+	assert.ErrorContains(t, err, "cannot unmarshal number into Go value of type []interface {}")
+}
+
+func TestFlightLeg_UnmarshalJSON_Object(t *testing.T) {
+	payload := `{"departure": "SFO", "arrival": "ORD", "flight_number": "UA 123"}`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, leg.Departure, AirportCode("SFO"))
+	assert.Equal(t, leg.Arrival, AirportCode("ORD"))
+}
+
+func TestFlightLeg_UnmarshalJSON_ObjectWithMissingAirportCode(t *testing.T) {
+	payload := `{"departure": "SFO"}`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, leg.Departure, AirportCode("SFO"))
+	assert.Equal(t, leg.Arrival, AirportCode(""))
+}
+
+func TestFlightLeg_UnmarshalJSON_ErrorObjectDepartureCodeIsNotAString(t *testing.T) {
+	payload := `{"departure": 5, "arrival": "ORD"}`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.ErrorContains(t, err, "departure code is not a string")
+}
+
+func TestFlightLeg_UnmarshalJSON_ErrorObjectArrivalCodeIsNotAString(t *testing.T) {
+	payload := `{"departure": "SFO", "arrival": ["ORD"]}`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.ErrorContains(t, err, "arrival code is not a string")
+}
+
+func TestFlightLeg_UnmarshalJSON_String(t *testing.T) {
+	payload := `"SFO-ORD"`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, leg.Departure, AirportCode("SFO"))
+	assert.Equal(t, leg.Arrival, AirportCode("ORD"))
+}
+
+func TestFlightLeg_UnmarshalJSON_ErrorStringNotInTheExpectedFormat(t *testing.T) {
+	for _, payload := range []string{`"SFO"`, `"SFO-ORD-MIA"`, `"SFO ORD"`} {
+		var leg FlightLeg
+
+		err := json.Unmarshal([]byte(payload), &leg)
+
+		assert.ErrorContains(t, err, "string is not in the DEPARTURE-ARRIVAL format")
+	}
+}
+
+func TestUnmarshalFlightLeg_Formats(t *testing.T) {
+	tests := []struct {
+		payload    string
+		wantFormat FlightLegFormat
+	}{
+		{`["SFO", "ORD"]`, FlightLegFormatArray},
+		{` {"departure": "SFO", "arrival": "ORD"}`, FlightLegFormatObject},
+		{`"SFO-ORD"`, FlightLegFormatString},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.wantFormat), func(t *testing.T) {
+			leg, format, err := UnmarshalFlightLeg([]byte(tt.payload), true)
+			assert.NoError(t, err)
+			assert.Equal(t, format, tt.wantFormat)
+			assert.Equal(t, leg, FlightLeg{Departure: "SFO", Arrival: "ORD"})
+		})
+	}
+}
+
+func TestUnmarshalFlightLeg_StrictRejectsUnknownFields(t *testing.T) {
+	payload := `{"departure": "SFO", "arrival": "ORD", "gate": "B7", "carrier": "UA"}`
+
+	_, _, err := UnmarshalFlightLeg([]byte(payload), true)
+
+	var legError *FlightLegError
+	assert.ErrorAs(t, err, &legError)
+	assert.ErrorIs(t, err, ErrUnknownFlightLegField)
+	assert.EqualError(t, err, `unable to unmarshal flight leg: unknown flight leg field "carrier"`)
+	assert.Equal(t, legError.Field, "carrier")
+	assert.Equal(t, legError.Value, "UA")
+}
+
+func TestFlightLeg_MarshalJSONFormat(t *testing.T) {
+	leg := &FlightLeg{
+		Departure: "SFO",
+		Arrival:   "ORD",
+	}
+
+	tests := []struct {
+		format      FlightLegFormat
+		wantPayload string
+	}{
+		{"", `["SFO","ORD"]`},
+		{FlightLegFormatArray, `["SFO","ORD"]`},
+		{FlightLegFormatObject, `{"departure":"SFO","arrival":"ORD"}`},
+		{FlightLegFormatString, `"SFO-ORD"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			payload, err := leg.MarshalJSONFormat(tt.format)
+			assert.NoError(t, err)
+			assert.Equal(t, string(payload), tt.wantPayload)
+		})
+	}
+
+	_, err := leg.MarshalJSONFormat("xml")
+	assert.EqualError(t, err, `unsupported flight leg format "xml"`)
 }
 
 func TestFlightLeg_UnmarshalJSON_ErrorMoreThan2AirportCodes(t *testing.T) {
@@ -105,7 +233,7 @@ func TestFlightLeg_Validate(t *testing.T) {
 	validate := validator.New()
 	assert.NoError(t, RegisterAirportCodeValidation(validate))
 
-	flightLeg := &FlightLeg{"ORD", "JFK"}
+	flightLeg := &FlightLeg{Departure: "ORD", Arrival: "JFK"}
 
 	assert.NoError(t, validate.Struct(flightLeg))
 }
@@ -114,7 +242,7 @@ func TestFlightLeg_Validate_EmptyDepartureAirportCode(t *testing.T) {
 	validate := validator.New()
 	assert.NoError(t, RegisterAirportCodeValidation(validate))
 
-	flightLeg := &FlightLeg{"", "JFK"}
+	flightLeg := &FlightLeg{Departure: "", Arrival: "JFK"}
 
 	assert.ErrorContains(
 		t, validate.Struct(flightLeg),
@@ -126,7 +254,7 @@ func TestFlightLeg_Validate_EmptyArrivalAirportCode(t *testing.T) {
 	validate := validator.New()
 	assert.NoError(t, RegisterAirportCodeValidation(validate))
 
-	flightLeg := &FlightLeg{"SFO", ""}
+	flightLeg := &FlightLeg{Departure: "SFO", Arrival: ""}
 
 	assert.ErrorContains(
 		t, validate.Struct(flightLeg),
@@ -138,7 +266,7 @@ func TestFlightLeg_Validate_InvalidDepartureAirportCode(t *testing.T) {
 	validate := validator.New()
 	assert.NoError(t, RegisterAirportCodeValidation(validate))
 
-	flightLeg := &FlightLeg{"MI6", "SFO"}
+	flightLeg := &FlightLeg{Departure: "MI6", Arrival: "SFO"}
 
 	assert.ErrorContains(
 		t, validate.Struct(flightLeg),
@@ -150,10 +278,170 @@ func TestFlightLeg_Validate_InvalidArrivalAirportCode(t *testing.T) {
 	validate := validator.New()
 	assert.NoError(t, RegisterAirportCodeValidation(validate))
 
-	flightLeg := &FlightLeg{"SFO", "MIIIA"}
+	flightLeg := &FlightLeg{Departure: "SFO", Arrival: "MIIIA"}
 
 	assert.ErrorContains(
 		t, validate.Struct(flightLeg),
 		"Error:Field validation for 'Arrival' failed on the 'airport_code' tag",
 	)
 }
+
+func TestFlightLeg_UnmarshalJSON_ErrorDescribesTheOffendingValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		wantPosition int
+		wantValue    interface{}
+	}{
+		{
+			name:         "not an array",
+			payload:      `5`,
+			wantPosition: -1,
+			wantValue:    json.RawMessage(`5`),
+		},
+		{
+			name:         "wrong number of entries",
+			payload:      `["SFO"]`,
+			wantPosition: -1,
+			wantValue:    []interface{}{"SFO"},
+		},
+		{
+			name:         "departure is not a string",
+			payload:      `[5, "ORD"]`,
+			wantPosition: 0,
+			wantValue:    float64(5),
+		},
+		{
+			name:         "arrival is not a string",
+			payload:      `["SFO", false]`,
+			wantPosition: 1,
+			wantValue:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var leg FlightLeg
+			err := leg.UnmarshalJSON([]byte(tt.payload))
+
+			var legError *FlightLegError
+			assert.ErrorAs(t, err, &legError)
+			assert.ErrorIs(t, err, ErrInvalidFlightLeg)
+			assert.Equal(t, legError.Position, tt.wantPosition)
+			assert.Equal(t, legError.Value, tt.wantValue)
+		})
+	}
+}
+
+func TestFlightLeg_UnmarshalJSON_ObjectWithTimes(t *testing.T) {
+	payload := `{
+		"departure": "SFO",
+		"arrival": "ORD",
+		"departure_time": "2024-03-25T08:05:00-07:00",
+		"arrival_time": "2024-03-25T14:10:00-05:00"
+	}`
+
+	var leg FlightLeg
+
+	err := json.Unmarshal([]byte(payload), &leg)
+
+	assert.NoError(t, err)
+	assert.True(t, leg.DepartureTime.Equal(time.Date(2024, 3, 25, 15, 5, 0, 0, time.UTC)))
+	assert.True(t, leg.ArrivalTime.Equal(time.Date(2024, 3, 25, 19, 10, 0, 0, time.UTC)))
+}
+
+func TestFlightLeg_UnmarshalJSON_ErrorInvalidTime(t *testing.T) {
+	for _, payload := range []string{
+		`{"departure": "SFO", "arrival": "ORD", "departure_time": "yesterday"}`,
+		`{"departure": "SFO", "arrival": "ORD", "arrival_time": 1711375800}`,
+	} {
+		var leg FlightLeg
+
+		err := json.Unmarshal([]byte(payload), &leg)
+
+		var legError *FlightLegError
+		assert.ErrorAs(t, err, &legError)
+		assert.ErrorIs(t, err, ErrInvalidFlightLegTime)
+		assert.Contains(t, []string{"departure_time", "arrival_time"}, legError.Field)
+	}
+}
+
+func TestFlightLeg_MarshalJSONFormat_ObjectWithTimes(t *testing.T) {
+	departureTime := time.Date(2024, 3, 25, 8, 5, 0, 0, time.FixedZone("PDT", -7*60*60))
+	leg := &FlightLeg{
+		Departure:     "SFO",
+		Arrival:       "ORD",
+		DepartureTime: &departureTime,
+	}
+
+	payload, err := leg.MarshalJSONFormat(FlightLegFormatObject)
+	assert.NoError(t, err)
+	assert.Equal(t, string(payload), `{"departure":"SFO","arrival":"ORD","departure_time":"2024-03-25T08:05:00-07:00"}`)
+
+	payload, err = leg.MarshalJSONFormat(FlightLegFormatArray)
+	assert.NoError(t, err)
+	assert.Equal(t, string(payload), `["SFO","ORD"]`)
+}
+
+func FuzzFlightLeg_UnmarshalJSON(f *testing.F) {
+	f.Add([]byte(`["SFO", "ATL"]`))
+	f.Add([]byte(`{"departure": "SFO", "arrival": "ATL"}`))
+	f.Add([]byte(`{"departure": "SFO", "arrival": "ATL", "departure_time": "2024-03-25T08:05:00-07:00"}`))
+	f.Add([]byte(`"SFO-ATL"`))
+	f.Add([]byte(`["SFO"]`))
+	f.Add([]byte(`[1, 2]`))
+	f.Add([]byte(`"SFO-ATL-EWR"`))
+	f.Add([]byte(`{"arrival": null}`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		leg, format, err := UnmarshalFlightLeg(data, false)
+
+		if err != nil {
+			var flightLegError *FlightLegError
+			assert.ErrorAs(t, err, &flightLegError)
+			assert.Equal(t, leg, FlightLeg{})
+			return
+		}
+
+		// UnmarshalJSON agrees with UnmarshalFlightLeg
+		var unmarshaled FlightLeg
+		assert.NoError(t, unmarshaled.UnmarshalJSON(data))
+		assertSameFlightLeg(t, unmarshaled, leg)
+
+		// encoding the flight leg back in the same format, then decoding it again, gives the same flight leg
+		encoded, err := leg.MarshalJSONFormat(format)
+		if err != nil {
+			t.Fatalf("MarshalJSONFormat(%v) failed for %+v: %v", format, leg, err)
+		}
+
+		decoded, decodedFormat, err := UnmarshalFlightLeg(encoded, true)
+		if err != nil {
+			t.Fatalf("UnmarshalFlightLeg(%s) failed: %v", encoded, err)
+		}
+		assert.Equal(t, decodedFormat, format)
+		assertSameFlightLeg(t, decoded, leg)
+	})
+}
+
+// assertSameFlightLeg compares times by the instant they represent, and the offset of their time zone
+func assertSameFlightLeg(t *testing.T, got, want FlightLeg) {
+	t.Helper()
+
+	assert.Equal(t, got.Departure, want.Departure)
+	assert.Equal(t, got.Arrival, want.Arrival)
+
+	for _, times := range [][2]*time.Time{{got.DepartureTime, want.DepartureTime}, {got.ArrivalTime, want.ArrivalTime}} {
+		gotTime, wantTime := times[0], times[1]
+		if gotTime == nil || wantTime == nil {
+			assert.Equal(t, gotTime, wantTime)
+			continue
+		}
+
+		assert.True(t, gotTime.Equal(*wantTime), "%v != %v", gotTime, wantTime)
+
+		_, gotOffset := gotTime.Zone()
+		_, wantOffset := wantTime.Zone()
+		assert.Equal(t, gotOffset, wantOffset)
+	}
+}
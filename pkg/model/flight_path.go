@@ -1,7 +1,224 @@
 package model
 
+import (
+	"encoding/json"
+	"slices"
+)
+
 type FlightPath struct {
 	Origin      AirportCode `json:"origin"`
 	Destination AirportCode `json:"destination"`
 	FlightLegs  []FlightLeg `json:"flight_legs"`
+	Warnings    []Warning   `json:"warnings,omitempty"`
+	Anomalies   []Anomaly   `json:"anomalies,omitempty"`
+	// Emissions is the total of the emissions estimates of the flight legs, if they were estimated
+	Emissions *Emissions `json:"emissions,omitempty"`
+
+	// FlightLegFormat is how flight legs are marshalled. If empty, they are marshalled as arrays.
+	FlightLegFormat FlightLegFormat `json:"-"`
+}
+
+func (p *FlightPath) MarshalJSON() ([]byte, error) {
+	legs, err := marshalFlightLegs(p.FlightLegs, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := marshalWarnings(p.Warnings, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies, err := marshalAnomalies(p.Anomalies, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Origin      AirportCode       `json:"origin"`
+		Destination AirportCode       `json:"destination"`
+		FlightLegs  []json.RawMessage `json:"flight_legs"`
+		Warnings    []json.RawMessage `json:"warnings,omitempty"`
+		Anomalies   []json.RawMessage `json:"anomalies,omitempty"`
+		Emissions   *Emissions        `json:"emissions,omitempty"`
+		// FlightLegEmissions has the estimate of each flight leg, in the same order as the flight legs
+		FlightLegEmissions []*Emissions `json:"flight_leg_emissions,omitempty"`
+	}{
+		Origin:             p.Origin,
+		Destination:        p.Destination,
+		FlightLegs:         legs,
+		Warnings:           warnings,
+		Anomalies:          anomalies,
+		Emissions:          p.Emissions,
+		FlightLegEmissions: flightLegEmissions(p.FlightLegs, p.FlightLegFormat),
+	})
+}
+
+// flightLegEmissions are the emissions estimates of the flight legs, for the formats that cannot carry them within each
+// flight leg. It returns nil if the format can, or if no flight leg has an estimate.
+func flightLegEmissions(flightLegs []FlightLeg, format FlightLegFormat) []*Emissions {
+	estimated := slices.ContainsFunc(flightLegs, func(leg FlightLeg) bool {
+		return leg.Emissions != nil
+	})
+	if format == FlightLegFormatObject || !estimated {
+		return nil
+	}
+
+	estimates := make([]*Emissions, len(flightLegs))
+	for i := range flightLegs {
+		estimates[i] = flightLegs[i].Emissions
+	}
+	return estimates
+}
+
+// Finding is something the API reports about a flight path that did not prevent it from being calculated, such as a
+// warning or an anomaly.
+type Finding struct {
+	// Err describes the finding. It may contain internal details, so it is never marshalled.
+	Err error
+	// Code and Message are the public description of the finding, which is up to the API
+	Code    string
+	Message string
+}
+
+// marshalFindings marshals each finding as the value given for it by view, which formats its flight legs.
+func marshalFindings[T any](findings []T, view func(*T) (interface{}, error)) ([]json.RawMessage, error) {
+	marshalled := make([]json.RawMessage, 0, len(findings))
+	for i := range findings {
+		value, err := view(&findings[i])
+		if err != nil {
+			return nil, err
+		}
+
+		finding, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		marshalled = append(marshalled, finding)
+	}
+	return marshalled, nil
+}
+
+// Warning is something unusual about a flight leg, which did not prevent the flight path from being calculated.
+type Warning struct {
+	Finding
+	FlightLeg FlightLeg
+}
+
+// marshalWarnings marshals the flight leg of each warning in the given format
+func marshalWarnings(warnings []Warning, format FlightLegFormat) ([]json.RawMessage, error) {
+	return marshalFindings(warnings, func(warning *Warning) (interface{}, error) {
+		leg, err := warning.FlightLeg.MarshalJSONFormat(format)
+		if err != nil {
+			return nil, err
+		}
+
+		return struct {
+			Code      string          `json:"code"`
+			Message   string          `json:"message"`
+			FlightLeg json.RawMessage `json:"flight_leg"`
+		}{
+			Code:      warning.Code,
+			Message:   warning.Message,
+			FlightLeg: leg,
+		}, nil
+	})
+}
+
+// AnomalySeverity tells how strongly an anomaly suggests that the flight path was not actually flown as given.
+type AnomalySeverity string
+
+const (
+	AnomalySeverityLow    AnomalySeverity = "low"
+	AnomalySeverityMedium AnomalySeverity = "medium"
+	AnomalySeverityHigh   AnomalySeverity = "high"
+)
+
+// Anomaly is a sign that the flight path could not have been flown by a single traveler, such as a flight leg that
+// would have been flown faster than any airliner can fly. Unlike warnings, anomalies are found after the flight path
+// is calculated, from the times of its flight legs.
+type Anomaly struct {
+	Finding
+	Severity AnomalySeverity
+	// FlightLegs are the flight legs the anomaly was found in, in the order they are flown
+	FlightLegs []FlightLeg
+}
+
+// marshalAnomalies marshals the flight legs of each anomaly in the given format
+func marshalAnomalies(anomalies []Anomaly, format FlightLegFormat) ([]json.RawMessage, error) {
+	return marshalFindings(anomalies, func(anomaly *Anomaly) (interface{}, error) {
+		legs, err := marshalFlightLegs(anomaly.FlightLegs, format)
+		if err != nil {
+			return nil, err
+		}
+
+		return struct {
+			Code       string            `json:"code"`
+			Severity   AnomalySeverity   `json:"severity"`
+			Message    string            `json:"message"`
+			FlightLegs []json.RawMessage `json:"flight_legs"`
+		}{
+			Code:       anomaly.Code,
+			Severity:   anomaly.Severity,
+			Message:    anomaly.Message,
+			FlightLegs: legs,
+		}, nil
+	})
+}
+
+// marshalFlightLegs marshals each flight leg in the given format. It returns nil if there are no flight legs at all.
+func marshalFlightLegs(flightLegs []FlightLeg, format FlightLegFormat) ([]json.RawMessage, error) {
+	if flightLegs == nil {
+		return nil, nil
+	}
+
+	legs := make([]json.RawMessage, 0, len(flightLegs))
+	for i := range flightLegs {
+		leg, err := flightLegs[i].MarshalJSONFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// MergedFlightPath is a flight path merged from the flight legs given by multiple sources.
+type MergedFlightPath struct {
+	FlightPath
+	// FlightLegSources has the sources of each flight leg, in the same order as the flight legs
+	FlightLegSources [][]string
+}
+
+func (p *MergedFlightPath) MarshalJSON() ([]byte, error) {
+	legs, err := marshalFlightLegs(p.FlightLegs, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := marshalWarnings(p.Warnings, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies, err := marshalAnomalies(p.Anomalies, p.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Origin           AirportCode       `json:"origin"`
+		Destination      AirportCode       `json:"destination"`
+		FlightLegs       []json.RawMessage `json:"flight_legs"`
+		FlightLegSources [][]string        `json:"flight_leg_sources"`
+		Warnings         []json.RawMessage `json:"warnings,omitempty"`
+		Anomalies        []json.RawMessage `json:"anomalies,omitempty"`
+	}{
+		Origin:           p.Origin,
+		Destination:      p.Destination,
+		FlightLegs:       legs,
+		FlightLegSources: p.FlightLegSources,
+		Warnings:         warnings,
+		Anomalies:        anomalies,
+	})
 }
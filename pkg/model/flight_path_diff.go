@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// FlightPathDiff is what changed from one flight path to another, such as when a traveler is rerouted.
+type FlightPathDiff struct {
+	Before *FlightPath
+	After  *FlightPath
+
+	OriginPreserved      bool
+	DestinationPreserved bool
+
+	// AddedFlightLegs are only in the flight path after, and RemovedFlightLegs are only in the flight path before,
+	// both in the order they are flown.
+	AddedFlightLegs   []FlightLeg
+	RemovedFlightLegs []FlightLeg
+
+	// AddedConnections and RemovedConnections are the airports where the traveler changes flights, which are only
+	// in the flight path after or before, respectively.
+	AddedConnections   []AirportCode
+	RemovedConnections []AirportCode
+
+	// FlightLegFormat is how flight legs are marshalled, including the ones of Before and After. If empty, they are
+	// marshalled as arrays.
+	FlightLegFormat FlightLegFormat `json:"-"`
+}
+
+func (d *FlightPathDiff) MarshalJSON() ([]byte, error) {
+	added, err := marshalFlightLegs(d.AddedFlightLegs, d.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := marshalFlightLegs(d.RemovedFlightLegs, d.FlightLegFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(struct {
+		Before               *FlightPath       `json:"before"`
+		After                *FlightPath       `json:"after"`
+		OriginPreserved      bool              `json:"origin_preserved"`
+		DestinationPreserved bool              `json:"destination_preserved"`
+		AddedFlightLegs      []json.RawMessage `json:"added_flight_legs"`
+		RemovedFlightLegs    []json.RawMessage `json:"removed_flight_legs"`
+		AddedConnections     []AirportCode     `json:"added_connections"`
+		RemovedConnections   []AirportCode     `json:"removed_connections"`
+	}{
+		Before:               d.withFlightLegFormat(d.Before),
+		After:                d.withFlightLegFormat(d.After),
+		OriginPreserved:      d.OriginPreserved,
+		DestinationPreserved: d.DestinationPreserved,
+		AddedFlightLegs:      added,
+		RemovedFlightLegs:    removed,
+		AddedConnections:     d.AddedConnections,
+		RemovedConnections:   d.RemovedConnections,
+	})
+}
+
+func (d *FlightPathDiff) withFlightLegFormat(flightPath *FlightPath) *FlightPath {
+	if flightPath == nil {
+		return nil
+	}
+	formatted := *flightPath
+	formatted.FlightLegFormat = d.FlightLegFormat
+	return &formatted
+}
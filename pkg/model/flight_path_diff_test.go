@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightPathDiff_MarshalJSON(t *testing.T) {
+	payload := &FlightPathDiff{
+		Before: &FlightPath{
+			Origin:      "SFO",
+			Destination: "GSO",
+			FlightLegs:  []FlightLeg{{Departure: "SFO", Arrival: "ATL"}, {Departure: "ATL", Arrival: "GSO"}},
+		},
+		After: &FlightPath{
+			Origin:      "SFO",
+			Destination: "GSO",
+			FlightLegs:  []FlightLeg{{Departure: "SFO", Arrival: "ORD"}, {Departure: "ORD", Arrival: "GSO"}},
+		},
+		OriginPreserved:      true,
+		DestinationPreserved: true,
+		AddedFlightLegs:      []FlightLeg{{Departure: "SFO", Arrival: "ORD"}, {Departure: "ORD", Arrival: "GSO"}},
+		RemovedFlightLegs:    []FlightLeg{{Departure: "SFO", Arrival: "ATL"}, {Departure: "ATL", Arrival: "GSO"}},
+		AddedConnections:     []AirportCode{"ORD"},
+		RemovedConnections:   []AirportCode{"ATL"},
+		FlightLegFormat:      FlightLegFormatString,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(jsonData), `{
+		"before": {"origin": "SFO", "destination": "GSO", "flight_legs": ["SFO-ATL", "ATL-GSO"]},
+		"after": {"origin": "SFO", "destination": "GSO", "flight_legs": ["SFO-ORD", "ORD-GSO"]},
+		"origin_preserved": true,
+		"destination_preserved": true,
+		"added_flight_legs": ["SFO-ORD", "ORD-GSO"],
+		"removed_flight_legs": ["SFO-ATL", "ATL-GSO"],
+		"added_connections": ["ORD"],
+		"removed_connections": ["ATL"]
+	}`)
+
+	// the flight paths themselves are left untouched
+	assert.Empty(t, payload.Before.FlightLegFormat)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,10 +13,10 @@ func TestFlightPath_MarshalJSON(t *testing.T) {
 		Origin:      "SFO",
 		Destination: "EWR",
 		FlightLegs: []FlightLeg{
-			{"SFO", "ATL"},
-			{"ATL", "GSO"},
-			{"GSO", "IND"},
-			{"IND", "EWR"},
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "GSO"},
+			{Departure: "GSO", Arrival: "IND"},
+			{Departure: "IND", Arrival: "EWR"},
 		},
 	}
 
@@ -26,3 +27,110 @@ func TestFlightPath_MarshalJSON(t *testing.T) {
 			`"flight_legs":[["SFO","ATL"],["ATL","GSO"],["GSO","IND"],["IND","EWR"]]}`,
 	)
 }
+
+func TestFlightPath_MarshalJSON_FlightLegFormat(t *testing.T) {
+	payload := &FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []FlightLeg{
+			{Departure: "SFO", Arrival: "ATL"},
+			{Departure: "ATL", Arrival: "GSO"},
+		},
+		FlightLegFormat: FlightLegFormatString,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, string(jsonData),
+		`{"origin":"SFO","destination":"GSO","flight_legs":["SFO-ATL","ATL-GSO"]}`,
+	)
+}
+
+func TestFlightPath_MarshalJSON_Emissions(t *testing.T) {
+	payload := &FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs: []FlightLeg{
+			{Departure: "SFO", Arrival: "ATL", Emissions: &Emissions{Distance: 3709, CO2: 556.4}},
+			{Departure: "ATL", Arrival: "GSO", Emissions: &Emissions{Distance: 532, CO2: 95.8}},
+		},
+		Emissions: &Emissions{Distance: 4241, CO2: 652.2},
+	}
+
+	tests := []struct {
+		format FlightLegFormat
+		want   string
+	}{
+		{FlightLegFormatArray, `{"origin":"SFO","destination":"GSO","flight_legs":[["SFO","ATL"],["ATL","GSO"]],` +
+			`"emissions":{"distance_km":4241,"co2_kg":652.2},"flight_leg_emissions":` +
+			`[{"distance_km":3709,"co2_kg":556.4},{"distance_km":532,"co2_kg":95.8}]}`},
+		{FlightLegFormatString, `{"origin":"SFO","destination":"GSO","flight_legs":["SFO-ATL","ATL-GSO"],` +
+			`"emissions":{"distance_km":4241,"co2_kg":652.2},"flight_leg_emissions":` +
+			`[{"distance_km":3709,"co2_kg":556.4},{"distance_km":532,"co2_kg":95.8}]}`},
+		{FlightLegFormatObject, `{"origin":"SFO","destination":"GSO","flight_legs":[` +
+			`{"departure":"SFO","arrival":"ATL","emissions":{"distance_km":3709,"co2_kg":556.4}},` +
+			`{"departure":"ATL","arrival":"GSO","emissions":{"distance_km":532,"co2_kg":95.8}}],` +
+			`"emissions":{"distance_km":4241,"co2_kg":652.2}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.format), func(t *testing.T) {
+			payload.FlightLegFormat = test.format
+
+			jsonData, err := json.Marshal(payload)
+			assert.NoError(t, err)
+			assert.Equal(t, string(jsonData), test.want)
+		})
+	}
+}
+
+func TestFlightPath_MarshalJSON_Warnings(t *testing.T) {
+	payload := &FlightPath{
+		Origin:      "SFO",
+		Destination: "ATL",
+		FlightLegs:  []FlightLeg{{Departure: "SFO", Arrival: "ATL"}},
+		Warnings: []Warning{{
+			Finding: Finding{
+				Err:     errors.New("duplicate flight leg from SFO to ATL"),
+				Code:    "duplicate_flight_leg",
+				Message: "The same flight leg was given more than once.",
+			},
+			FlightLeg: FlightLeg{Departure: "SFO", Arrival: "ATL"},
+		}},
+		FlightLegFormat: FlightLegFormatObject,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, string(jsonData),
+		`{"origin":"SFO","destination":"ATL","flight_legs":[{"departure":"SFO","arrival":"ATL"}],`+
+			`"warnings":[{"code":"duplicate_flight_leg","message":"The same flight leg was given more than once.",`+
+			`"flight_leg":{"departure":"SFO","arrival":"ATL"}}]}`,
+	)
+}
+
+func TestFlightPath_MarshalJSON_Anomalies(t *testing.T) {
+	payload := &FlightPath{
+		Origin:      "SFO",
+		Destination: "GSO",
+		FlightLegs:  []FlightLeg{{Departure: "SFO", Arrival: "ATL"}, {Departure: "ATL", Arrival: "GSO"}},
+		Anomalies: []Anomaly{{
+			Finding: Finding{
+				Err:     errors.New("overlapping flight legs at ATL"),
+				Code:    "overlapping_flight_legs",
+				Message: "A flight leg departs before the one before it arrives.",
+			},
+			Severity:   AnomalySeverityHigh,
+			FlightLegs: []FlightLeg{{Departure: "SFO", Arrival: "ATL"}, {Departure: "ATL", Arrival: "GSO"}},
+		}},
+		FlightLegFormat: FlightLegFormatString,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, string(jsonData),
+		`{"origin":"SFO","destination":"GSO","flight_legs":["SFO-ATL","ATL-GSO"],`+
+			`"anomalies":[{"code":"overlapping_flight_legs","severity":"high",`+
+			`"message":"A flight leg departs before the one before it arrives.","flight_legs":["SFO-ATL","ATL-GSO"]}]}`,
+	)
+}
@@ -0,0 +1,138 @@
+package network
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// The columns of an airports.dat file of OpenFlights, see https://openflights.org/data.php#airport. Only the columns
+// up to the longitude are required, since older versions of the dataset have fewer columns after it.
+const (
+	airportIDColumn = iota
+	airportNameColumn
+	airportCityColumn
+	airportCountryColumn
+	airportIATAColumn
+	airportICAOColumn
+	airportLatitudeColumn
+	airportLongitudeColumn
+	airportRequiredColumns
+)
+
+var ErrInvalidAirport = errors.New("invalid airport")
+
+type Airport struct {
+	Code        model.AirportCode
+	Name        string
+	City        string
+	Country     string
+	Coordinates model.Coordinates
+}
+
+// Airports is a directory of airports by their IATA code, such as the one of the airports dataset of OpenFlights.
+type Airports struct {
+	byCode map[model.AirportCode]Airport
+}
+
+func NewAirports() *Airports {
+	return &Airports{byCode: make(map[model.AirportCode]Airport)}
+}
+
+// LoadAirports reads an airports dataset in the format of the airports.dat file of OpenFlights, which is CSV without a
+// header, such as:
+//
+//	3469,"San Francisco International Airport","San Francisco","United States","SFO","KSFO",37.619,-122.375,...
+//
+// Airports without an IATA code, which have \N instead, are skipped.
+func LoadAirports(r io.Reader) (*Airports, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	airports := NewAirports()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return airports, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAirport, err)
+		}
+
+		airport, err := parseAirport(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("%w on line %v: %w", ErrInvalidAirport, line, err)
+		}
+		if airport.Code.IsValid() {
+			airports.Add(airport)
+		}
+	}
+}
+
+func parseAirport(record []string) (Airport, error) {
+	if len(record) < airportRequiredColumns {
+		return Airport{}, fmt.Errorf("expected at least %v fields, got %v", airportRequiredColumns, len(record))
+	}
+
+	latitude, err := strconv.ParseFloat(record[airportLatitudeColumn], 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return Airport{}, fmt.Errorf("invalid latitude %q", record[airportLatitudeColumn])
+	}
+	longitude, err := strconv.ParseFloat(record[airportLongitudeColumn], 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return Airport{}, fmt.Errorf("invalid longitude %q", record[airportLongitudeColumn])
+	}
+
+	return Airport{
+		Code:        model.AirportCode(record[airportIATAColumn]),
+		Name:        record[airportNameColumn],
+		City:        record[airportCityColumn],
+		Country:     record[airportCountryColumn],
+		Coordinates: model.Coordinates{Latitude: latitude, Longitude: longitude},
+	}, nil
+}
+
+// LoadAirportsFile is like LoadAirports, but reads the dataset from a file.
+func LoadAirportsFile(path string) (*Airports, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	airports, err := LoadAirports(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return airports, nil
+}
+
+// Add adds the airport, replacing any other airport with the same code.
+func (a *Airports) Add(airport Airport) {
+	airport.Code = normalizeAirportCode(airport.Code)
+	a.byCode[airport.Code] = airport
+}
+
+// Airport is the airport with the code, in any case, if it is known.
+func (a *Airports) Airport(code model.AirportCode) (Airport, bool) {
+	airport, ok := a.byCode[normalizeAirportCode(code)]
+	return airport, ok
+}
+
+// Coordinates are the location of the airport, if it is known.
+func (a *Airports) Coordinates(code model.AirportCode) (model.Coordinates, bool) {
+	airport, ok := a.byCode[normalizeAirportCode(code)]
+	return airport.Coordinates, ok
+}
+
+// Len is the number of airports.
+func (a *Airports) Len() int {
+	return len(a.byCode)
+}
@@ -0,0 +1,99 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const airportsData = `3469,"San Francisco International Airport","San Francisco","United States","SFO","KSFO",37.61899948120117,-122.375,13,-8,"A","America/Los_Angeles","airport","OurAirports"
+3682,"Hartsfield Jackson Atlanta International Airport","Atlanta","United States","ATL","KATL",33.6367,-84.428101,1026,-5,"A","America/New_York"
+6891,"Putnam County Airport","Greencastle","United States",\N,"K4I7",39.6335,-86.8138,842,-5,"U","America/New_York","airport","OurAirports"
+`
+
+func TestLoadAirports(t *testing.T) {
+	airports, err := LoadAirports(strings.NewReader(airportsData))
+	assert.NoError(t, err)
+
+	assert.Equal(t, airports.Len(), 2, "airports without an IATA code are skipped")
+
+	sfo, ok := airports.Airport("SFO")
+	assert.True(t, ok)
+	assert.Equal(t, sfo, Airport{
+		Code:        "SFO",
+		Name:        "San Francisco International Airport",
+		City:        "San Francisco",
+		Country:     "United States",
+		Coordinates: model.Coordinates{Latitude: 37.61899948120117, Longitude: -122.375},
+	})
+
+	coordinates, ok := airports.Coordinates("ATL")
+	assert.True(t, ok)
+	assert.Equal(t, coordinates, model.Coordinates{Latitude: 33.6367, Longitude: -84.428101})
+
+	_, ok = airports.Coordinates("GSO")
+	assert.False(t, ok)
+}
+
+func TestAirports_LowerCaseAirportCodes(t *testing.T) {
+	airports := NewAirports()
+	airports.Add(Airport{Code: "sfo", Coordinates: model.Coordinates{Latitude: 37.619, Longitude: -122.375}})
+
+	sfo, ok := airports.Airport("SFO")
+	assert.True(t, ok)
+	assert.Equal(t, sfo.Code, model.AirportCode("SFO"))
+
+	coordinates, ok := airports.Coordinates("sfo")
+	assert.True(t, ok)
+	assert.Equal(t, coordinates, model.Coordinates{Latitude: 37.619, Longitude: -122.375})
+}
+
+func TestLoadAirports_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantError string
+	}{
+		{
+			name:      "too few fields",
+			data:      `3469,"San Francisco International Airport","San Francisco","United States","SFO","KSFO",37.619`,
+			wantError: "invalid airport on line 1: expected at least 8 fields, got 7",
+		},
+		{
+			name: "invalid latitude",
+			data: `3682,"Atlanta","Atlanta","United States","ATL","KATL",33.6,-84.4` + "\n" +
+				`3469,"San Francisco","San Francisco","United States","SFO","KSFO",north,-122.375`,
+			wantError: `invalid airport on line 2: invalid latitude "north"`,
+		},
+		{
+			name:      "longitude out of range",
+			data:      `3469,"San Francisco","San Francisco","United States","SFO","KSFO",37.619,-222.375`,
+			wantError: `invalid airport on line 1: invalid longitude "-222.375"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			airports, err := LoadAirports(strings.NewReader(test.data))
+			assert.Nil(t, airports)
+			assert.ErrorIs(t, err, ErrInvalidAirport)
+			assert.EqualError(t, err, test.wantError)
+		})
+	}
+}
+
+func TestLoadAirportsFile(t *testing.T) {
+	airports, err := LoadAirportsFile("../../data/airports.dat")
+	assert.NoError(t, err)
+
+	// every airport of the routes dataset has coordinates
+	routes, err := LoadRoutesFile("../../data/routes.dat")
+	assert.NoError(t, err)
+	for code := range routes.airports {
+		_, ok := airports.Coordinates(code)
+		assert.True(t, ok, "missing airport %v", code)
+	}
+}
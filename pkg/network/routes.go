@@ -0,0 +1,165 @@
+package network
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+// The columns of a routes.dat file of OpenFlights, see https://openflights.org/data.php#route
+const (
+	routeAirlineColumn = iota
+	routeAirlineIDColumn
+	routeSourceColumn
+	routeSourceIDColumn
+	routeDestinationColumn
+	routeDestinationIDColumn
+	routeCodeshareColumn
+	routeStopsColumn
+	routeEquipmentColumn
+	routeColumns
+)
+
+const (
+	// unknownAirportPlausibility is how plausible a route is when the network knows nothing about one of its
+	// airports, which is most likely missing from the dataset.
+	unknownAirportPlausibility = 0.5
+	// unknownRoutePlausibility is how plausible a route is when the network knows both of its airports, but no
+	// airline flies between them.
+	unknownRoutePlausibility = 0.1
+)
+
+var ErrInvalidRoute = errors.New("invalid route")
+
+// Routes is a network of nonstop routes between airports, such as the one of the routes dataset of OpenFlights. Routes
+// are directed: a flight from SFO to ATL does not mean there's one from ATL to SFO.
+type Routes struct {
+	// destinations has the destinations of every airport with at least one departing route
+	destinations map[model.AirportCode]map[model.AirportCode]bool
+	// airports has every airport with at least one departing or arriving route
+	airports map[model.AirportCode]bool
+	count    int
+}
+
+func NewRoutes() *Routes {
+	return &Routes{
+		destinations: make(map[model.AirportCode]map[model.AirportCode]bool),
+		airports:     make(map[model.AirportCode]bool),
+	}
+}
+
+// LoadRoutes reads a routes dataset in the format of the routes.dat file of OpenFlights, which is CSV without a
+// header, such as:
+//
+//	DL,2009,SFO,3469,ATL,3682,,0,757 321
+//
+// Only nonstop routes between IATA airport codes are loaded. Routes with stops, or between airports that are only
+// known by their ICAO codes, are skipped.
+func LoadRoutes(r io.Reader) (*Routes, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = routeColumns
+	reader.ReuseRecord = true
+
+	routes := NewRoutes()
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return routes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRoute, err)
+		}
+
+		source := model.AirportCode(record[routeSourceColumn])
+		destination := model.AirportCode(record[routeDestinationColumn])
+		if record[routeStopsColumn] != "0" || !source.IsValid() || !destination.IsValid() {
+			continue
+		}
+		routes.Add(source, destination)
+	}
+}
+
+// LoadRoutesFile is like LoadRoutes, but reads the dataset from a file.
+func LoadRoutesFile(path string) (*Routes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	routes, err := LoadRoutes(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return routes, nil
+}
+
+// Add adds the route from the departure to the arrival. Adding the same route more than once, such as when it is
+// flown by more than one airline, has no effect.
+func (r *Routes) Add(departure, arrival model.AirportCode) {
+	departure, arrival = normalizeAirportCode(departure), normalizeAirportCode(arrival)
+	destinations, ok := r.destinations[departure]
+	if !ok {
+		destinations = make(map[model.AirportCode]bool)
+		r.destinations[departure] = destinations
+	}
+	if !destinations[arrival] {
+		destinations[arrival] = true
+		r.count++
+	}
+
+	r.airports[departure] = true
+	r.airports[arrival] = true
+}
+
+// HasRoute tells if some airline flies nonstop from the departure to the arrival.
+func (r *Routes) HasRoute(departure, arrival model.AirportCode) bool {
+	return r.destinations[normalizeAirportCode(departure)][normalizeAirportCode(arrival)]
+}
+
+// HasAirport tells if some route departs from, or arrives at, the airport.
+func (r *Routes) HasAirport(airport model.AirportCode) bool {
+	return r.airports[normalizeAirportCode(airport)]
+}
+
+// Destinations are the airports that can be flown to nonstop from the departure, in alphabetical order.
+func (r *Routes) Destinations(departure model.AirportCode) []model.AirportCode {
+	departure = normalizeAirportCode(departure)
+	destinations := make([]model.AirportCode, 0, len(r.destinations[departure]))
+	for destination := range r.destinations[departure] {
+		destinations = append(destinations, destination)
+	}
+	slices.Sort(destinations)
+	return destinations
+}
+
+// Len is the number of distinct routes.
+func (r *Routes) Len() int {
+	return r.count
+}
+
+// Plausibility tells how plausible it is that a traveler flew from the departure to the arrival: 1 if some airline
+// flies the route, somewhat plausible if the network does not know one of the airports, and barely plausible if it
+// knows both, but no airline flies between them.
+func (r *Routes) Plausibility(departure, arrival model.AirportCode) float64 {
+	switch {
+	case r.HasRoute(departure, arrival):
+		return 1
+	case !r.HasAirport(departure) || !r.HasAirport(arrival):
+		return unknownAirportPlausibility
+	default:
+		return unknownRoutePlausibility
+	}
+}
+
+// normalizeAirportCode upper cases the airport code, since airport codes are valid in any case, but datasets have them
+// in upper case.
+func normalizeAirportCode(code model.AirportCode) model.AirportCode {
+	return model.AirportCode(strings.ToUpper(string(code)))
+}
@@ -0,0 +1,89 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+const routesData = `DL,2009,SFO,3469,ATL,3682,,0,757
+UA,5209,SFO,3469,ATL,3682,Y,0,320
+DL,2009,ATL,3682,GSO,4007,,0,CR9
+DL,2009,SFO,3469,GSO,4007,,1,757 CR9
+AC,330,CYUL,146,KJFK,3797,,0,E75
+`
+
+func TestLoadRoutes(t *testing.T) {
+	routes, err := LoadRoutes(strings.NewReader(routesData))
+	assert.NoError(t, err)
+
+	assert.Equal(t, routes.Len(), 2)
+	assert.True(t, routes.HasRoute("SFO", "ATL"))
+	assert.True(t, routes.HasRoute("ATL", "GSO"))
+	assert.False(t, routes.HasRoute("ATL", "SFO"), "routes are directed")
+	assert.False(t, routes.HasRoute("SFO", "GSO"), "routes with stops are skipped")
+	assert.False(t, routes.HasAirport("CYUL"), "ICAO codes are skipped")
+	assert.Equal(t, routes.Destinations("SFO"), []model.AirportCode{"ATL"})
+	assert.Equal(t, routes.Destinations("GSO"), []model.AirportCode{})
+}
+
+func TestLoadRoutes_Invalid(t *testing.T) {
+	routes, err := LoadRoutes(strings.NewReader("DL,2009,SFO,3469,ATL\n"))
+	assert.Nil(t, routes)
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+}
+
+func TestLoadRoutesFile(t *testing.T) {
+	routes, err := LoadRoutesFile("../../data/routes.dat")
+	assert.NoError(t, err)
+	assert.True(t, routes.HasRoute("SFO", "ATL"))
+	assert.False(t, routes.HasRoute("SFO", "GSO"))
+
+	_, err = LoadRoutesFile("../../data/missing.dat")
+	assert.Error(t, err)
+}
+
+func TestRoutes_Destinations(t *testing.T) {
+	routes := NewRoutes()
+	routes.Add("SFO", "ORD")
+	routes.Add("SFO", "ATL")
+	routes.Add("SFO", "EWR")
+	routes.Add("SFO", "ATL")
+
+	assert.Equal(t, routes.Len(), 3)
+	assert.Equal(t, routes.Destinations("SFO"), []model.AirportCode{"ATL", "EWR", "ORD"})
+}
+
+func TestRoutes_LowerCaseAirportCodes(t *testing.T) {
+	routes := NewRoutes()
+	routes.Add("sfo", "ATL")
+
+	assert.True(t, routes.HasRoute("SFO", "atl"))
+	assert.True(t, routes.HasAirport("sfo"))
+	assert.Equal(t, routes.Destinations("sfo"), []model.AirportCode{"ATL"})
+	assert.Equal(t, routes.Plausibility("sfo", "atl"), 1.0)
+}
+
+func TestRoutes_Plausibility(t *testing.T) {
+	routes := NewRoutes()
+	routes.Add("SFO", "ATL")
+	routes.Add("GSO", "IND")
+
+	tests := []struct {
+		departure, arrival model.AirportCode
+		want               float64
+	}{
+		{"SFO", "ATL", 1},
+		{"ATL", "GSO", unknownRoutePlausibility},
+		{"ATL", "LHR", unknownAirportPlausibility},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.departure+"-"+test.arrival), func(t *testing.T) {
+			assert.Equal(t, routes.Plausibility(test.departure, test.arrival), test.want)
+		})
+	}
+}
@@ -1,30 +1,154 @@
 package validator
 
 import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/pt"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"github.com/go-playground/validator/v10/non-standard/validators"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
+	ptTranslations "github.com/go-playground/validator/v10/translations/pt"
 
 	"github.com/felipead/flight-path-tracker/pkg/model"
 )
 
 var singleton *validator.Validate
+var translators *ut.UniversalTranslator
+
+type languageTranslations struct {
+	registerDefaults func(*validator.Validate, ut.Translator) error
+	messages         map[string]string
+}
+
+// translations holds, for each supported language, the messages of the validation tags defined by this application.
+// The messages of the standard tags come from go-playground.
+var translations = map[string]languageTranslations{
+	"en": {
+		registerDefaults: enTranslations.RegisterDefaultTranslations,
+		messages: map[string]string{
+			"airport_code": "{0} must be a 3-letter IATA airport code",
+			"notblank":     "{0} cannot be blank",
+		},
+	},
+	"es": {
+		registerDefaults: esTranslations.RegisterDefaultTranslations,
+		messages: map[string]string{
+			"airport_code": "{0} debe ser un código de aeropuerto IATA de 3 letras",
+			"notblank":     "{0} no puede estar vacío",
+		},
+	},
+	"pt": {
+		registerDefaults: ptTranslations.RegisterDefaultTranslations,
+		messages: map[string]string{
+			"airport_code": "{0} deve ser um código de aeroporto IATA de 3 letras",
+			"notblank":     "{0} não pode estar em branco",
+		},
+	},
+}
 
 // InitValidator is not thread-safe and must be invoked before the server start serving requests.
 func InitValidator() (err error) {
 	if singleton == nil {
-		singleton = validator.New()
+		validate := validator.New()
+		validate.RegisterTagNameFunc(fieldName)
 
-		if err = model.RegisterAirportCodeValidation(singleton); err != nil {
+		if err = model.RegisterAirportCodeValidation(validate); err != nil {
 			return
 		}
 
-		if err = singleton.RegisterValidation("notblank", validators.NotBlank); err != nil {
+		if err = validate.RegisterValidation("notblank", validators.NotBlank); err != nil {
 			return
 		}
+
+		if err = registerTranslations(validate); err != nil {
+			return
+		}
+
+		singleton = validate
 	}
 	return
 }
 
+// fieldName is the name of the struct field in JSON, so that validation errors never show the names of Go struct
+// fields. Fields without a json tag, such as those of types with their own JSON marshalling, are named in snake case.
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return snakeCase(field.Name)
+	default:
+		return name
+	}
+}
+
+func snakeCase(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func registerTranslations(validate *validator.Validate) error {
+	english := en.New()
+	universal := ut.New(english, english, es.New(), pt.New())
+
+	for language, t := range translations {
+		translator, _ := universal.GetTranslator(language)
+
+		if err := t.registerDefaults(validate, translator); err != nil {
+			return err
+		}
+
+		for tag, message := range t.messages {
+			if err := validate.RegisterTranslation(tag, translator, registerMessage(tag, message), translateMessage); err != nil {
+				return err
+			}
+		}
+	}
+
+	translators = universal
+	return nil
+}
+
+func registerMessage(tag, message string) validator.RegisterTranslationsFunc {
+	return func(translator ut.Translator) error {
+		return translator.Add(tag, message, true)
+	}
+}
+
+func translateMessage(translator ut.Translator, fieldError validator.FieldError) string {
+	message, err := translator.T(fieldError.Tag(), fieldError.Field())
+	if err != nil {
+		return fieldError.Error()
+	}
+	return message
+}
+
 func GetValidator() *validator.Validate {
 	return singleton
 }
+
+// GetTranslator returns the translator of validation errors for the given language, such as "es", falling back to
+// English if the language is not supported. It returns nil if InitValidator was not invoked yet.
+func GetTranslator(language string) ut.Translator {
+	if translators == nil {
+		return nil
+	}
+	translator, _ := translators.GetTranslator(language)
+	return translator
+}
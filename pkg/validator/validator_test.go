@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/flight-path-tracker/pkg/model"
+)
+
+func TestGetTranslator(t *testing.T) {
+	assert.NoError(t, InitValidator())
+
+	err := GetValidator().Struct(&model.FlightLeg{Departure: "", Arrival: "O5D"})
+
+	var validationErrors validator.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Len(t, validationErrors, 2)
+
+	tests := []struct {
+		language     string
+		wantRequired string
+		wantAirport  string
+	}{
+		{"en", "departure is a required field", "arrival must be a 3-letter IATA airport code"},
+		{"es", "departure es un campo requerido", "arrival debe ser un código de aeropuerto IATA de 3 letras"},
+		{"pt", "departure é obrigatório", "arrival deve ser um código de aeroporto IATA de 3 letras"},
+		{"de", "departure is a required field", "arrival must be a 3-letter IATA airport code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			translator := GetTranslator(tt.language)
+			assert.Equal(t, validationErrors[0].Translate(translator), tt.wantRequired)
+			assert.Equal(t, validationErrors[1].Translate(translator), tt.wantAirport)
+		})
+	}
+}